@@ -0,0 +1,101 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package echo
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/swagger"
+)
+
+// Router is implemented by both *echo.Echo and *echo.Group
+type Router interface {
+	Add(method, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route
+}
+
+type route struct {
+	method     string
+	path       string
+	handler    echo.HandlerFunc
+	middleware []echo.MiddlewareFunc
+}
+
+// Register binds every endpoint of the api to the router, translating path parameters into the :name syntax. Handlers
+// may be an echo.HandlerFunc, func(echo.Context) error or http.Handler; endpoint middleware may be an
+// echo.MiddlewareFunc, func(echo.HandlerFunc) echo.HandlerFunc or func(http.Handler) http.Handler. Nothing is
+// registered if any endpoint has a handler or middleware of another type
+func Register(api *swagger.API, router Router) error {
+	var routes []route
+	var err error
+	api.Walk(func(path string, e *swagger.Endpoint) {
+		if err != nil {
+			return
+		}
+
+		r := route{method: e.Method, path: swag.ColonPath(path)}
+		for _, m := range e.Middleware {
+			mw, ok := middleware(m)
+			if !ok {
+				err = fmt.Errorf("%v %v: unsupported middleware type %T", e.Method, path, m)
+				return
+			}
+			r.middleware = append(r.middleware, mw)
+		}
+
+		h, ok := handler(e.Handler)
+		if !ok {
+			err = fmt.Errorf("%v %v: unsupported handler type %T", e.Method, path, e.Handler)
+			return
+		}
+		r.handler = h
+		routes = append(routes, r)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range routes {
+		router.Add(r.method, r.path, r.handler, r.middleware...)
+	}
+	return nil
+}
+
+func handler(v interface{}) (echo.HandlerFunc, bool) {
+	switch h := v.(type) {
+	case echo.HandlerFunc:
+		return h, true
+	case func(echo.Context) error:
+		return h, true
+	case http.Handler:
+		return echo.WrapHandler(h), true
+	default:
+		return nil, false
+	}
+}
+
+func middleware(v interface{}) (echo.MiddlewareFunc, bool) {
+	switch m := v.(type) {
+	case echo.MiddlewareFunc:
+		return m, true
+	case func(echo.HandlerFunc) echo.HandlerFunc:
+		return m, true
+	case func(http.Handler) http.Handler:
+		return echo.WrapMiddleware(m), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package echo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/echo"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	api := swag.New(
+		swag.BasePath("/api"),
+		swag.Endpoints(
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.Handler(func(c echo.Context) error {
+					return c.String(http.StatusOK, c.Param("petId"))
+				}),
+				endpoint.Middleware(
+					func(next echo.HandlerFunc) echo.HandlerFunc {
+						return func(c echo.Context) error {
+							c.Response().Header().Set("X-Order", c.Response().Header().Get("X-Order")+"echo,")
+							return next(c)
+						}
+					},
+					func(next http.Handler) http.Handler {
+						return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+							w.Header().Set("X-Order", w.Header().Get("X-Order")+"http")
+							next.ServeHTTP(w, req)
+						})
+					},
+				),
+			),
+			endpoint.New("delete", "/pet/{petId}", "Deletes a pet",
+				endpoint.Handler(func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+		),
+	)
+
+	router := echo.New()
+	assert.Nil(t, adapter.Register(api, router))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/pet/123", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "123", w.Body.String())
+	assert.Equal(t, "echo,http", w.Header().Get("X-Order"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/pet/123", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRegisterInvalidMiddleware(t *testing.T) {
+	api := swag.New(
+		swag.Endpoints(
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.Handler(func(c echo.Context) error { return nil }),
+				endpoint.Middleware(42),
+			),
+		),
+	)
+
+	router := echo.New()
+	assert.EqualError(t, adapter.Register(api, router), "GET /pet/{petId}: unsupported middleware type int")
+	assert.Empty(t, router.Routes())
+}
@@ -0,0 +1,34 @@
+// Package echo binds swag definitions to echo routers
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package echo
+
+import (
+	"github.com/labstack/echo"
+	"github.com/miketonks/swag/validate"
+)
+
+// Validate returns echo middleware that rejects requests which do not match their endpoint definition
+func Validate(v *validate.Validator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := v.Check(c.Request()); err != nil {
+				v.Reject(c.Response(), c.Request(), err)
+				return nil
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/swagger"
+)
+
+type route struct {
+	method   string
+	path     string
+	handlers []gin.HandlerFunc
+}
+
+// Register binds every endpoint of the api to the router, translating path parameters into the :name syntax. Handlers
+// may be a gin.HandlerFunc, func(*gin.Context) or http.Handler; endpoint middleware may be a gin.HandlerFunc,
+// func(*gin.Context) or func(http.Handler) http.Handler. Nothing is registered if any endpoint has a handler or
+// middleware of another type
+func Register(api *swagger.API, router gin.IRoutes) error {
+	var routes []route
+	var err error
+	api.Walk(func(path string, e *swagger.Endpoint) {
+		if err != nil {
+			return
+		}
+
+		r := route{method: e.Method, path: swag.ColonPath(path)}
+		for _, m := range e.Middleware {
+			h, ok := middleware(m)
+			if !ok {
+				err = fmt.Errorf("%v %v: unsupported middleware type %T", e.Method, path, m)
+				return
+			}
+			r.handlers = append(r.handlers, h)
+		}
+
+		h, ok := handler(e.Handler)
+		if !ok {
+			err = fmt.Errorf("%v %v: unsupported handler type %T", e.Method, path, e.Handler)
+			return
+		}
+		r.handlers = append(r.handlers, h)
+		routes = append(routes, r)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range routes {
+		router.Handle(r.method, r.path, r.handlers...)
+	}
+	return nil
+}
+
+func handler(v interface{}) (gin.HandlerFunc, bool) {
+	switch h := v.(type) {
+	case gin.HandlerFunc:
+		return h, true
+	case func(*gin.Context):
+		return h, true
+	case http.Handler:
+		return gin.WrapH(h), true
+	default:
+		return nil, false
+	}
+}
+
+func middleware(v interface{}) (gin.HandlerFunc, bool) {
+	switch m := v.(type) {
+	case gin.HandlerFunc:
+		return m, true
+	case func(*gin.Context):
+		return m, true
+	case func(http.Handler) http.Handler:
+		return func(c *gin.Context) {
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				called = true
+				c.Request = req
+				c.Next()
+			})
+			m(next).ServeHTTP(c.Writer, c.Request)
+			if !called {
+				c.Abort()
+			}
+		}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/gin"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	api := swag.New(
+		swag.BasePath("/api"),
+		swag.Endpoints(
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.Handler(func(c *gin.Context) {
+					c.String(http.StatusOK, c.Param("petId"))
+				}),
+				endpoint.Middleware(
+					func(c *gin.Context) {
+						c.Header("X-Order", c.Writer.Header().Get("X-Order")+"gin,")
+					},
+					func(next http.Handler) http.Handler {
+						return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+							w.Header().Set("X-Order", w.Header().Get("X-Order")+"http")
+							next.ServeHTTP(w, req)
+						})
+					},
+				),
+			),
+			endpoint.New("delete", "/pet/{petId}", "Deletes a pet",
+				endpoint.Handler(func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				}),
+				endpoint.Middleware(func(next http.Handler) http.Handler {
+					return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+						w.WriteHeader(http.StatusForbidden)
+					})
+				}),
+			),
+		),
+	)
+
+	router := gin.New()
+	assert.Nil(t, adapter.Register(api, router))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/pet/123", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "123", w.Body.String())
+	assert.Equal(t, "gin,http", w.Header().Get("X-Order"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/pet/123", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRegisterInvalidHandler(t *testing.T) {
+	api := swag.New(
+		swag.Endpoints(
+			endpoint.New("get", "/pets", "List pets", endpoint.Handler(func(c *gin.Context) {})),
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID", endpoint.Handler("oops")),
+		),
+	)
+
+	router := gin.New()
+	assert.EqualError(t, adapter.Register(api, router), "GET /pet/{petId}: unsupported handler type string")
+	assert.Empty(t, router.Routes())
+}
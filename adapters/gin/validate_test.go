@@ -0,0 +1,52 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package gin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/gin"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/validate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	api := swag.New(
+		swag.Endpoints(endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+			endpoint.Path("petId", "integer", "int64", "ID of pet to return"),
+		)),
+	)
+
+	router := gin.New()
+	router.Use(adapter.Validate(validate.New(api)))
+	router.GET("/pet/:petId", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pet/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pet/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
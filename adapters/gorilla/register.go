@@ -0,0 +1,70 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gorilla
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/miketonks/swag/swagger"
+)
+
+type route struct {
+	method  string
+	path    string
+	handler http.Handler
+}
+
+// Register binds every endpoint of the api to the router; gorilla shares the {name} path syntax of swagger, so paths
+// are used as is. Handlers must be an http.Handler; endpoint middleware may be a mux.MiddlewareFunc or
+// func(http.Handler) http.Handler. Nothing is registered if any endpoint has a handler or middleware of another type
+func Register(api *swagger.API, router *mux.Router) error {
+	var routes []route
+	var err error
+	api.Walk(func(path string, e *swagger.Endpoint) {
+		if err != nil {
+			return
+		}
+
+		h, ok := e.Handler.(http.Handler)
+		if !ok {
+			err = fmt.Errorf("%v %v: unsupported handler type %T", e.Method, path, e.Handler)
+			return
+		}
+
+		for i := len(e.Middleware) - 1; i >= 0; i-- {
+			m := e.Middleware[i]
+			switch mw := m.(type) {
+			case mux.MiddlewareFunc:
+				h = mw(h)
+			case func(http.Handler) http.Handler:
+				h = mw(h)
+			default:
+				err = fmt.Errorf("%v %v: unsupported middleware type %T", e.Method, path, m)
+				return
+			}
+		}
+
+		routes = append(routes, route{method: e.Method, path: path, handler: h})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range routes {
+		router.Handle(r.path, r.handler).Methods(r.method)
+	}
+	return nil
+}
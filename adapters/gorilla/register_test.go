@@ -0,0 +1,75 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gorilla_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/gorilla"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	api := swag.New(
+		swag.BasePath("/api"),
+		swag.Endpoints(
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.Handler(func(w http.ResponseWriter, req *http.Request) {
+					io.WriteString(w, mux.Vars(req)["petId"])
+				}),
+				endpoint.Middleware(
+					mux.MiddlewareFunc(func(next http.Handler) http.Handler {
+						return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+							w.Header().Set("X-Order", w.Header().Get("X-Order")+"mux,")
+							next.ServeHTTP(w, req)
+						})
+					}),
+					func(next http.Handler) http.Handler {
+						return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+							w.Header().Set("X-Order", w.Header().Get("X-Order")+"http")
+							next.ServeHTTP(w, req)
+						})
+					},
+				),
+			),
+		),
+	)
+
+	router := mux.NewRouter()
+	assert.Nil(t, adapter.Register(api, router))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/pet/123", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "123", w.Body.String())
+	assert.Equal(t, "mux,http", w.Header().Get("X-Order"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/pet/123", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestRegisterInvalidHandler(t *testing.T) {
+	api := swag.New(
+		swag.Endpoints(endpoint.New("get", "/pets", "List pets", endpoint.Handler(func() {}))),
+	)
+
+	assert.EqualError(t, adapter.Register(api, mux.NewRouter()), "GET /pets: unsupported handler type func()")
+}
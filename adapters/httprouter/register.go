@@ -0,0 +1,103 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package httprouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/swagger"
+)
+
+type route struct {
+	method string
+	path   string
+	handle httprouter.Handle
+}
+
+// Register binds every endpoint of the api to the router, translating path parameters into the :name syntax. Handlers
+// may be an httprouter.Handle, func(http.ResponseWriter, *http.Request, httprouter.Params) or http.Handler; endpoint
+// middleware may be a func(httprouter.Handle) httprouter.Handle or func(http.Handler) http.Handler. Standard handlers
+// and middleware find the path parameters with httprouter.ParamsFromContext. Nothing is registered if any endpoint has
+// a handler or middleware of another type
+func Register(api *swagger.API, router *httprouter.Router) error {
+	var routes []route
+	var err error
+	api.Walk(func(path string, e *swagger.Endpoint) {
+		if err != nil {
+			return
+		}
+
+		h, ok := handle(e.Handler)
+		if !ok {
+			err = fmt.Errorf("%v %v: unsupported handler type %T", e.Method, path, e.Handler)
+			return
+		}
+
+		for i := len(e.Middleware) - 1; i >= 0; i-- {
+			m := e.Middleware[i]
+			switch mw := m.(type) {
+			case func(httprouter.Handle) httprouter.Handle:
+				h = mw(h)
+			case func(http.Handler) http.Handler:
+				h = toHandle(mw(toHandler(h)))
+			default:
+				err = fmt.Errorf("%v %v: unsupported middleware type %T", e.Method, path, m)
+				return
+			}
+		}
+
+		routes = append(routes, route{method: e.Method, path: swag.ColonPath(path), handle: h})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range routes {
+		router.Handle(r.method, r.path, r.handle)
+	}
+	return nil
+}
+
+func handle(v interface{}) (httprouter.Handle, bool) {
+	switch h := v.(type) {
+	case httprouter.Handle:
+		return h, true
+	case func(http.ResponseWriter, *http.Request, httprouter.Params):
+		return h, true
+	case http.Handler:
+		return toHandle(h), true
+	default:
+		return nil, false
+	}
+}
+
+// toHandle adapts a standard handler, making the params available via httprouter.ParamsFromContext
+func toHandle(h http.Handler) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if len(params) > 0 {
+			req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, params))
+		}
+		h.ServeHTTP(w, req)
+	}
+}
+
+func toHandler(h httprouter.Handle) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		h(w, req, httprouter.ParamsFromContext(req.Context()))
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package httprouter_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/httprouter"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	api := swag.New(
+		swag.BasePath("/api"),
+		swag.Endpoints(
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.Handler(func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+					io.WriteString(w, params.ByName("petId"))
+				}),
+				endpoint.Middleware(
+					func(next httprouter.Handle) httprouter.Handle {
+						return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+							w.Header().Set("X-Order", w.Header().Get("X-Order")+"httprouter,")
+							next(w, req, params)
+						}
+					},
+					func(next http.Handler) http.Handler {
+						return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+							w.Header().Set("X-Order", w.Header().Get("X-Order")+"http")
+							next.ServeHTTP(w, req)
+						})
+					},
+				),
+			),
+			endpoint.New("delete", "/pet/{petId}", "Deletes a pet",
+				endpoint.Handler(func(w http.ResponseWriter, req *http.Request) {
+					io.WriteString(w, httprouter.ParamsFromContext(req.Context()).ByName("petId"))
+				}),
+			),
+		),
+	)
+
+	router := httprouter.New()
+	assert.Nil(t, adapter.Register(api, router))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/pet/123", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "123", w.Body.String())
+	assert.Equal(t, "httprouter,http", w.Header().Get("X-Order"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/pet/456", nil))
+	assert.Equal(t, "456", w.Body.String())
+}
+
+func TestRegisterInvalidHandler(t *testing.T) {
+	api := swag.New(
+		swag.Endpoints(endpoint.New("get", "/pets", "List pets")),
+	)
+
+	assert.EqualError(t, adapter.Register(api, httprouter.New()), "GET /pets: unsupported handler type <nil>")
+}
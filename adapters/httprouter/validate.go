@@ -0,0 +1,35 @@
+// Package httprouter binds swag definitions to httprouter routers
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package httprouter
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/miketonks/swag/validate"
+)
+
+// Validate wraps an httprouter.Handle so that requests which do not match their endpoint definition are rejected
+// before reaching it
+func Validate(v *validate.Validator, h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if err := v.Check(req); err != nil {
+			v.Reject(w, req, err)
+			return
+		}
+		h(w, req, params)
+	}
+}
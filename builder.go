@@ -137,6 +137,14 @@ func Endpoints(endpoints ...*swagger.Endpoint) Option {
 	}
 }
 
+// Registry generates the definitions of the endpoints, other than those with their own registry, with the registry
+// rather than the package level settings
+func Registry(r *swagger.Registry) Option {
+	return func(builder *Builder) {
+		builder.API.SetRegistry(r)
+	}
+}
+
 // SecurityScheme creates a new security definition for the API.
 func SecurityScheme(name string, options ...swagger.SecuritySchemeOption) Option {
 	scheme := swagger.SecurityScheme{}
@@ -188,8 +196,31 @@ func Security(scheme string, scopes ...string) Option {
 	}
 }
 
-// New constructs a new api builder
+// New constructs a new api builder; it panics if an option is invalid, see NewE
 func New(options ...Option) *swagger.API {
+	api, err := NewE(options...)
+	if err != nil {
+		panic(err)
+	}
+	return api
+}
+
+// NewE is like New, but returns an error, e.g. a *swagger.APIError for an endpoint with an invalid method or a
+// *swagger.SchemaError for an invalid tag value, rather than panicking; other panics are not recovered
+func NewE(options ...Option) (api *swagger.API, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			switch e := v.(type) {
+			case *swagger.SchemaError:
+				api, err = nil, e
+			case *swagger.APIError:
+				api, err = nil, e
+			default:
+				panic(v)
+			}
+		}
+	}()
+
 	b := &Builder{
 		API: &swagger.API{
 			BasePath: "/",
@@ -205,5 +236,5 @@ func New(options ...Option) *swagger.API {
 		opt(b)
 	}
 
-	return b.API
+	return b.API, nil
 }
@@ -15,6 +15,7 @@
 package swag_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/miketonks/swag"
@@ -218,3 +219,25 @@ func apiHasParam(a *swagger.API, name string) bool {
 
 	return found
 }
+
+func TestNewE(t *testing.T) {
+	api, err := swag.NewE(swag.Endpoints(endpoint.New("get", "/", "get")))
+	assert.Nil(t, err)
+	assert.NotNil(t, api)
+
+	api, err = swag.NewE(swag.Endpoints(endpoint.New("fetch", "/", "fetch")))
+	assert.EqualError(t, err, "invalid method, FETCH")
+	assert.Nil(t, api)
+
+	assert.Panics(t, func() {
+		swag.New(swag.Endpoints(endpoint.New("fetch", "/", "fetch")))
+	})
+
+	// other panics are not turned into errors
+	assert.PanicsWithValue(t, "broken option", func() {
+		swag.NewE(func(*swag.Builder) { panic("broken option") })
+	})
+	assert.PanicsWithError(t, "not a definition error", func() {
+		swag.NewE(func(*swag.Builder) { panic(errors.New("not a definition error")) })
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command swagdiff compares two versions of a swagger 2.0 document and reports the changes between them. It exits
+// with status 1 when any of the changes would break existing clients.
+//
+//	swagdiff [-json] [-breaking] old.yaml new.yaml
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/miketonks/swag/swagger"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the changes as json")
+	breakingOnly := flag.Bool("breaking", false, "only print breaking changes")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: swagdiff [-json] [-breaking] old new\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	before, err := swagger.LoadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	after, err := swagger.LoadFile(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	changes := swagger.Diff(before, after)
+	breaking := changes.Breaking()
+	if *breakingOnly {
+		changes = breaking
+	}
+
+	if *asJSON {
+		if changes == nil {
+			changes = swagger.Changes{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	} else if len(changes) > 0 {
+		fmt.Println(changes)
+	}
+
+	if len(breaking) > 0 {
+		os.Exit(1)
+	}
+}
@@ -34,6 +34,34 @@ const (
 type Builder struct {
 	Endpoint  *swagger.Endpoint
 	paramType int
+	schemas   []*swagger.Schema
+	err       error
+}
+
+// fail records the first error found while applying the options
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// makeSchema makes the schema of t with the registry of the endpoint, keeping track of it in case the registry is set
+// by a later option
+func (b *Builder) makeSchema(t reflect.Type) *swagger.Schema {
+	s, err := b.Endpoint.Registry.MakeSchemaE(t)
+	if err != nil {
+		b.fail(err)
+		return &swagger.Schema{Prototype: t}
+	}
+	b.schemas = append(b.schemas, s)
+	return s
+}
+
+// mixedParameters reports an endpoint with both form and body parameters
+func (b *Builder) mixedParameters() error {
+	return &swagger.APIError{
+		Err: fmt.Errorf("%v %v: cannot mix form and body parameters", b.Endpoint.Method, b.Endpoint.Path),
+	}
 }
 
 // ensureParamType ensures we cannot mix form and body data
@@ -41,12 +69,12 @@ func (b *Builder) ensureParamType(in string) {
 	switch in {
 	case "formData":
 		if b.paramType == typeJSON {
-			panic(fmt.Errorf(`Cannot mix form and body parameters`))
+			b.fail(b.mixedParameters())
 		}
 		b.paramType = typeForm
 	case "body":
 		if b.paramType == typeForm {
-			panic(fmt.Errorf(`Cannot mix form and body parameters`))
+			b.fail(b.mixedParameters())
 		}
 		b.paramType = typeJSON
 	}
@@ -61,8 +89,8 @@ func (o Option) Apply(builder *Builder) {
 }
 
 // Handler allows an instance of the web handler to be associated with the endpoint.  This can be especially useful when
-// using swag to bind the endpoints to the web router.  See the examples package for how the Handler can be used in
-// conjunction with Walk to simplify binding endpoints to a router
+// using swag to bind the endpoints to the web router.  See the Register functions of the adapters packages, which bind
+// every endpoint of the api to a router, and the examples package for how they are used
 func Handler(handler interface{}) Option {
 	return func(b *Builder) {
 		if v, ok := handler.(func(w http.ResponseWriter, r *http.Request)); ok {
@@ -72,6 +100,31 @@ func Handler(handler interface{}) Option {
 	}
 }
 
+// Middleware associates router specific middleware with the endpoint, e.g. gin.HandlerFunc or echo.MiddlewareFunc, or
+// standard func(http.Handler) http.Handler middleware. The middleware is applied, outermost first, when the endpoint is
+// bound with the Register function of one of the adapters packages
+func Middleware(middleware ...interface{}) Option {
+	return func(b *Builder) {
+		b.Endpoint.Middleware = append(b.Endpoint.Middleware, middleware...)
+	}
+}
+
+// Registry generates the schemas of the body and responses, and the definitions they refer to, with the registry rather
+// than the package level settings
+func Registry(r *swagger.Registry) Option {
+	return func(b *Builder) {
+		b.Endpoint.Registry = r
+		for _, s := range b.schemas {
+			remade, err := r.MakeSchemaE(s.Prototype)
+			if err != nil {
+				b.fail(err)
+				continue
+			}
+			*s = *remade
+		}
+	}
+}
+
 // Description sets the endpoint's description
 func Description(v string) Option {
 	return func(b *Builder) {
@@ -100,9 +153,128 @@ func Consumes(v ...string) Option {
 	}
 }
 
-func parameter(p swagger.Parameter) Option {
+// ParamOption customizes a parameter defined by Path, RequestHeader, Query or FormData
+type ParamOption func(p *swagger.Parameter)
+
+// Apply improves the readability of applied options
+func (o ParamOption) Apply(p *swagger.Parameter) {
+	o(p)
+}
+
+// Enum sets the possible values of the parameter
+func Enum(values ...interface{}) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Enum = values
+	}
+}
+
+// EnumOf sets the possible values of the parameter, and their names, to those of an enum type registered with
+// swagger.RegisterEnum or implementing swagger.SwaggerEnumProvider
+func EnumOf(v interface{}) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Enum, p.EnumVarNames = swagger.EnumOf(v)
+	}
+}
+
+// Default sets the value the server uses when the parameter isn't sent
+func Default(v interface{}) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Default = v
+	}
+}
+
+// Pattern sets the regular expression a string parameter must match
+func Pattern(expr string) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Pattern = expr
+	}
+}
+
+// Minimum sets the minimum value of a number parameter
+func Minimum(v float64) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Minimum = &v
+	}
+}
+
+// Maximum sets the maximum value of a number parameter
+func Maximum(v float64) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Maximum = &v
+	}
+}
+
+// MinLength sets the minimum length of a string parameter
+func MinLength(n int) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.MinLength = n
+	}
+}
+
+// MaxLength sets the maximum length of a string parameter
+func MaxLength(n int) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.MaxLength = n
+	}
+}
+
+// Items sets the type, format and possible values of the items of an array parameter
+func Items(typ, format string, enum ...interface{}) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Items = &swagger.Items{Type: typ, Format: format, Enum: enum}
+	}
+}
+
+// CollectionFormat sets how the items of an array parameter are separated: csv, ssv, tsv, pipes, or multi for repeated
+// query or form data parameters
+func CollectionFormat(format string) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.CollectionFormat = format
+	}
+}
+
+// AllowEmptyValue allows a query or form data parameter to be sent without a value
+func AllowEmptyValue() ParamOption {
+	return func(p *swagger.Parameter) {
+		p.AllowEmptyValue = true
+	}
+}
+
+// ParamExample sets an example value of the parameter
+func ParamExample(v interface{}) ParamOption {
+	return func(p *swagger.Parameter) {
+		p.Example = v
+	}
+}
+
+// checkParameter reports options that don't apply to the location of the parameter
+func checkParameter(p swagger.Parameter) error {
+	switch p.CollectionFormat {
+	case "", "csv", "ssv", "tsv", "pipes":
+	case "multi":
+		if p.In != "query" && p.In != "formData" {
+			return fmt.Errorf(`%v parameter %v: collectionFormat "multi" is only valid for query or formData parameters`, p.In, p.Name)
+		}
+	default:
+		return fmt.Errorf(`%v parameter %v: collectionFormat must be one of csv, ssv, tsv, pipes or multi, got %q`, p.In, p.Name, p.CollectionFormat)
+	}
+	if p.AllowEmptyValue && p.In != "query" && p.In != "formData" {
+		return fmt.Errorf(`%v parameter %v: allowEmptyValue is only valid for query or formData parameters`, p.In, p.Name)
+	}
+	return nil
+}
+
+func parameter(p swagger.Parameter, options ...ParamOption) Option {
+	for _, opt := range options {
+		opt.Apply(&p)
+	}
+
 	return func(b *Builder) {
 		b.ensureParamType(p.In)
+		if err := checkParameter(p); err != nil {
+			b.fail(err)
+			return
+		}
 		if b.Endpoint.Parameters == nil {
 			b.Endpoint.Parameters = []swagger.Parameter{}
 		}
@@ -111,9 +283,9 @@ func parameter(p swagger.Parameter) Option {
 	}
 }
 
-// Path defines a path parameter for the endpoint; name, typ, format, description, and required correspond to the matching
-// swagger fields
-func Path(name, typ, format, description string) Option {
+// Path defines a path parameter for the endpoint; name, typ, format, and description correspond to the matching swagger
+// fields, and options set the others, e.g. Enum or Pattern
+func Path(name, typ, format, description string, options ...ParamOption) Option {
 	p := swagger.Parameter{
 		Name:        name,
 		In:          "path",
@@ -122,17 +294,18 @@ func Path(name, typ, format, description string) Option {
 		Description: description,
 		Required:    true,
 	}
-	return parameter(p)
+	return parameter(p, options...)
 }
 
-// PathMap allows us to define multiple path parameters in a map / struct format
+// PathMap allows us to define multiple path parameters in a map / struct format; parameters are added in name order
 func PathMap(params map[string]swagger.Parameter) Option {
 	return func(b *Builder) {
 		if b.Endpoint.Parameters == nil {
 			b.Endpoint.Parameters = []swagger.Parameter{}
 		}
 
-		for k, v := range params {
+		for _, k := range sortedNames(params) {
+			v := params[k]
 			v.Name = k
 			v.In = "path"
 			v.Required = true
@@ -142,7 +315,8 @@ func PathMap(params map[string]swagger.Parameter) Option {
 }
 
 // RequestHeader defines a header parameter for the endpoint; name, typ, format, description, and required correspond to the matching
-func RequestHeader(name, typ, format, description string, required bool) Option {
+// swagger fields, and options set the others
+func RequestHeader(name, typ, format, description string, required bool, options ...ParamOption) Option {
 	p := swagger.Parameter{
 		Name:        name,
 		In:          "header",
@@ -151,12 +325,17 @@ func RequestHeader(name, typ, format, description string, required bool) Option
 		Description: description,
 		Required:    required,
 	}
-	return parameter(p)
+	return parameter(p, options...)
 }
 
 // Query defines a query parameter for the endpoint; name, typ, format, description, and required correspond to the matching
-// swagger fields
-func Query(name, typ, format, description string, required bool) Option {
+// swagger fields, and options set the others, e.g.
+//
+//	endpoint.Query("status", "array", "", "statuses to filter by", false,
+//		endpoint.Items("string", "", "available", "sold"),
+//		endpoint.CollectionFormat("multi"),
+//	)
+func Query(name, typ, format, description string, required bool, options ...ParamOption) Option {
 	p := swagger.Parameter{
 		Name:        name,
 		In:          "query",
@@ -165,7 +344,7 @@ func Query(name, typ, format, description string, required bool) Option {
 		Description: description,
 		Required:    required,
 	}
-	return parameter(p)
+	return parameter(p, options...)
 }
 
 // QueryList allows us to define multiple query parameters in a []struct format
@@ -177,7 +356,8 @@ func QueryList(params []swagger.Parameter) Option {
 
 		for i, param := range params {
 			if param.Name == "" {
-				panic(fmt.Errorf(`QueryList parameter %d: %#v has an empty name`, i, param))
+				b.fail(fmt.Errorf(`QueryList parameter %d: %#v has an empty name`, i, param))
+				continue
 			}
 			param.In = "query"
 			b.Endpoint.Parameters = append(b.Endpoint.Parameters, param)
@@ -185,14 +365,15 @@ func QueryList(params []swagger.Parameter) Option {
 	}
 }
 
-// QueryMap allows us to define multiple query parameters in a map / struct format
+// QueryMap allows us to define multiple query parameters in a map / struct format; parameters are added in name order
 func QueryMap(params map[string]swagger.Parameter) Option {
 	return func(b *Builder) {
 		if b.Endpoint.Parameters == nil {
 			b.Endpoint.Parameters = []swagger.Parameter{}
 		}
 
-		for k, v := range params {
+		for _, k := range sortedNames(params) {
+			v := params[k]
 			v.Name = k
 			v.In = "query"
 			b.Endpoint.Parameters = append(b.Endpoint.Parameters, v)
@@ -201,8 +382,8 @@ func QueryMap(params map[string]swagger.Parameter) Option {
 }
 
 // FormData defines a form data parameter for the endpoint; name, typ, format, description, and required correspond to the matching
-// swagger fields
-func FormData(name, typ, format, description string, required bool) Option {
+// swagger fields, and options set the others
+func FormData(name, typ, format, description string, required bool, options ...ParamOption) Option {
 	p := swagger.Parameter{
 		Name:        name,
 		In:          "formData",
@@ -211,10 +392,11 @@ func FormData(name, typ, format, description string, required bool) Option {
 		Description: description,
 		Required:    required,
 	}
-	return parameter(p)
+	return parameter(p, options...)
 }
 
-// FormDataMap allows us to define multiple form data parameters in a map / struct format
+// FormDataMap allows us to define multiple form data parameters in a map / struct format; parameters are added in name
+// order
 func FormDataMap(params map[string]swagger.Parameter) Option {
 	return func(b *Builder) {
 		b.ensureParamType("formData")
@@ -222,7 +404,8 @@ func FormDataMap(params map[string]swagger.Parameter) Option {
 			b.Endpoint.Parameters = []swagger.Parameter{}
 		}
 
-		for k, v := range params {
+		for _, k := range sortedNames(params) {
+			v := params[k]
 			v.Name = k
 			v.In = "formData"
 			b.Endpoint.Parameters = append(b.Endpoint.Parameters, v)
@@ -234,14 +417,16 @@ func FormDataMap(params map[string]swagger.Parameter) Option {
 // prototype should be a struct or a pointer to struct that swag can use to reflect upon the return type
 // t represents the Type of the body
 func BodyType(t reflect.Type, description string, required bool) Option {
-	p := swagger.Parameter{
-		In:          "body",
-		Name:        "body",
-		Description: description,
-		Schema:      swagger.MakeSchema(t),
-		Required:    required,
+	return func(b *Builder) {
+		p := swagger.Parameter{
+			In:          "body",
+			Name:        "body",
+			Description: description,
+			Schema:      b.makeSchema(t),
+			Required:    required,
+		}
+		parameter(p)(b)
 	}
-	return parameter(p)
 }
 
 // Body defines a body parameter for the swagger endpoint as would commonly be used for the POST, PUT, and PATCH methods
@@ -329,13 +514,24 @@ func HeaderMap(headers map[string]swagger.Header) ResponseOption {
 	}
 }
 
+// Example adds an example of the response body for the mime type, e.g. application/json
+func Example(mimeType string, example interface{}) ResponseOption {
+	return func(response *swagger.Response) {
+		if response.Examples == nil {
+			response.Examples = map[string]interface{}{}
+		}
+
+		response.Examples[mimeType] = example
+	}
+}
+
 type fileMarker struct{}
 
 // ResponseFile can be used in an endpoint to change the type to 'file'
 var ResponseFile fileMarker
 
 // ResponseType sets the endpoint response for the specified code; may be used multiple times with different status codes
-// t represents the Type of the response
+// t represents the Type of the response, or nil for a response without a body
 func ResponseType(code int, t reflect.Type, description string, opts ...ResponseOption) Option {
 	return func(b *Builder) {
 		if b.Endpoint.Responses == nil {
@@ -346,13 +542,13 @@ func ResponseType(code int, t reflect.Type, description string, opts ...Response
 			Description: description,
 		}
 
-		if t.Kind() == reflect.Struct && t == reflect.TypeOf(fileMarker{}) {
+		if t == reflect.TypeOf(fileMarker{}) {
 			r.Schema = &swagger.Schema{
 				Type:      "file",
 				Prototype: "",
 			}
-		} else if t.Kind() != reflect.String {
-			r.Schema = swagger.MakeSchema(t)
+		} else if t != nil {
+			r.Schema = b.makeSchema(t)
 		}
 
 		for _, opt := range opts {
@@ -363,13 +559,29 @@ func ResponseType(code int, t reflect.Type, description string, opts ...Response
 	}
 }
 
-// Response sets the endpoint response for the specified code; may be used multiple times with different status codes
+// Response sets the endpoint response for the specified code; may be used multiple times with different status codes.
+// A nil or empty string prototype, e.g. Response(http.StatusNoContent, "", "deleted"), describes a response without a
+// body; any other string describes a string body
 func Response(code int, prototype interface{}, description string, opts ...ResponseOption) Option {
+	if prototype == nil || prototype == "" {
+		return ResponseType(code, nil, description, opts...)
+	}
 	return ResponseType(code, reflect.TypeOf(prototype), description, opts...)
 }
 
-// New constructs a new swagger endpoint using the fields and functional options provided
+// New constructs a new swagger endpoint using the fields and functional options provided; it panics if an option is
+// invalid, see Build
 func New(method, path, summary string, options ...Option) *swagger.Endpoint {
+	e, err := Build(method, path, summary, options...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Build is like New, but returns the first error found in the options, e.g. mixed form and body parameters or a
+// *swagger.SchemaError for an invalid tag value of the body or a response, rather than panicking
+func Build(method, path, summary string, options ...Option) (*swagger.Endpoint, error) {
 	method = strings.ToUpper(method)
 	e := &Builder{
 		Endpoint: &swagger.Endpoint{
@@ -387,5 +599,8 @@ func New(method, path, summary string, options ...Option) *swagger.Endpoint {
 		opt.Apply(e)
 	}
 
-	return e.Endpoint
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Endpoint, nil
 }
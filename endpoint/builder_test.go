@@ -326,3 +326,184 @@ func TestInvalidParamType(t *testing.T) {
 		)
 	})
 }
+
+func TestMapOptionsAreSorted(t *testing.T) {
+	params := map[string]swagger.Parameter{
+		"c": {Type: "string"},
+		"a": {Type: "string"},
+		"b": {Type: "string"},
+	}
+
+	e := endpoint.New("post", "/{a}/{b}/{c}", "sorted",
+		endpoint.PathMap(params),
+		endpoint.QueryMap(params),
+		endpoint.FormDataMap(params),
+	)
+
+	var names []string
+	for _, p := range e.Parameters {
+		names = append(names, p.In+":"+p.Name)
+	}
+	assert.Equal(t, []string{
+		"path:a", "path:b", "path:c",
+		"query:a", "query:b", "query:c",
+		"formData:a", "formData:b", "formData:c",
+	}, names)
+}
+
+func TestRegistry(t *testing.T) {
+	r := swagger.NewRegistry()
+	r.UsePackageName = true
+
+	e := endpoint.New("get", "/", "get thing",
+		endpoint.Response(http.StatusOK, Model{}, "successful"),
+		endpoint.Body(Model{}, "the description", true),
+		endpoint.Registry(r),
+	)
+
+	assert.Equal(t, r, e.Registry)
+	assert.Equal(t, "#/definitions/github_com__miketonks__swag__endpoint_test_Model", e.Responses["200"].Schema.Ref)
+	assert.Equal(t, "#/definitions/github_com__miketonks__swag__endpoint_test_Model", e.Parameters[0].Schema.Ref)
+}
+
+type BadTag struct {
+	Limit int `json:"limit" maximum:"ten"`
+}
+
+func TestBuild(t *testing.T) {
+	e, err := endpoint.Build("get", "/", "get thing", endpoint.Response(http.StatusOK, Model{}, "successful"))
+	assert.Nil(t, err)
+	assert.Equal(t, "#/definitions/Model", e.Responses["200"].Schema.Ref)
+
+	_, err = endpoint.Build("post", "/", "post thing",
+		endpoint.FormData("foo", "string", "", "desc", false),
+		endpoint.Body(Model{}, "desc", false),
+	)
+	var apiErr *swagger.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.EqualError(t, err, "POST /: cannot mix form and body parameters")
+
+	_, err = endpoint.Build("get", "/", "get thing", endpoint.Response(http.StatusOK, BadTag{}, "successful"))
+	var schemaErr *swagger.SchemaError
+	assert.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, reflect.TypeOf(BadTag{}), schemaErr.Type)
+	assert.Equal(t, "Limit", schemaErr.Field)
+	assert.Equal(t, "maximum", schemaErr.Tag)
+	assert.EqualError(t, err, `endpoint_test.BadTag: field Limit: tag maximum: strconv.ParseFloat: parsing "ten": invalid syntax`)
+}
+
+func TestResponseExample(t *testing.T) {
+	e := endpoint.New("get", "/", "get thing",
+		endpoint.Response(http.StatusOK, Model{}, "successful",
+			endpoint.Example("application/json", map[string]interface{}{"id": 1}),
+		),
+	)
+
+	assert.Equal(t, map[string]interface{}{
+		"application/json": map[string]interface{}{"id": 1},
+	}, e.Responses["200"].Examples)
+}
+
+func TestParamOptions(t *testing.T) {
+	e := endpoint.New("get", "/pet/{id}", "get thing",
+		endpoint.Path("id", "integer", "int64", "the id", endpoint.Minimum(1)),
+		endpoint.Query("status", "array", "", "statuses to filter by", false,
+			endpoint.Items("string", "", "available", "sold"),
+			endpoint.CollectionFormat("multi"),
+		),
+		endpoint.Query("sort", "string", "", "sort order", false,
+			endpoint.Enum("asc", "desc"),
+			endpoint.Default("asc"),
+			endpoint.AllowEmptyValue(),
+		),
+		endpoint.RequestHeader("X-Request-ID", "string", "", "request id", true,
+			endpoint.Pattern("^[a-f0-9]+$"),
+			endpoint.MinLength(8),
+			endpoint.MaxLength(32),
+			endpoint.ParamExample("deadbeef"),
+		),
+	)
+
+	min := 1.0
+	assert.Equal(t, []swagger.Parameter{
+		{In: "path", Name: "id", Description: "the id", Required: true, Type: "integer", Format: "int64", Minimum: &min},
+		{
+			In:               "query",
+			Name:             "status",
+			Description:      "statuses to filter by",
+			Type:             "array",
+			Items:            &swagger.Items{Type: "string", Enum: []interface{}{"available", "sold"}},
+			CollectionFormat: "multi",
+		},
+		{
+			In:              "query",
+			Name:            "sort",
+			Description:     "sort order",
+			Type:            "string",
+			Enum:            []interface{}{"asc", "desc"},
+			Default:         "asc",
+			AllowEmptyValue: true,
+		},
+		{
+			In:          "header",
+			Name:        "X-Request-ID",
+			Description: "request id",
+			Required:    true,
+			Type:        "string",
+			Pattern:     "^[a-f0-9]+$",
+			MinLength:   8,
+			MaxLength:   32,
+			Example:     "deadbeef",
+		},
+	}, e.Parameters)
+}
+
+func TestInvalidParamOptions(t *testing.T) {
+	_, err := endpoint.Build("get", "/", "get thing",
+		endpoint.RequestHeader("X-Tags", "array", "", "tags", false, endpoint.CollectionFormat("multi")),
+	)
+	assert.EqualError(t, err, `header parameter X-Tags: collectionFormat "multi" is only valid for query or formData parameters`)
+
+	_, err = endpoint.Build("get", "/", "get thing",
+		endpoint.Query("tags", "array", "", "tags", false, endpoint.CollectionFormat("commas")),
+	)
+	assert.EqualError(t, err, `query parameter tags: collectionFormat must be one of csv, ssv, tsv, pipes or multi, got "commas"`)
+
+	_, err = endpoint.Build("get", "/{id}", "get thing",
+		endpoint.Path("id", "string", "", "the id", endpoint.AllowEmptyValue()),
+	)
+	assert.EqualError(t, err, `path parameter id: allowEmptyValue is only valid for query or formData parameters`)
+}
+
+func TestInlineResponses(t *testing.T) {
+	e := endpoint.New("get", "/", "get thing",
+		endpoint.Response(http.StatusOK, []string{}, "ids"),
+		endpoint.Response(http.StatusAccepted, map[string]int{}, "counters"),
+		endpoint.Response(http.StatusCreated, int64(0), "id"),
+		endpoint.Response(http.StatusConflict, false, "flag"),
+		endpoint.Response(http.StatusBadRequest, "message", "reason"),
+		endpoint.Response(http.StatusNoContent, "", "nothing"),
+	)
+
+	assert.Equal(t, &swagger.Schema{
+		Type:      "array",
+		Items:     &swagger.Items{Type: "string"},
+		Prototype: reflect.TypeOf([]string{}),
+	}, e.Responses["200"].Schema)
+
+	counters := e.Responses["202"].Schema
+	assert.Equal(t, "object", counters.Type)
+	assert.Equal(t, "integer", counters.AdditionalProperties.(*swagger.Property).Type)
+
+	assert.Equal(t, "integer", e.Responses["201"].Schema.Type)
+	assert.Equal(t, "int64", e.Responses["201"].Schema.Format)
+	assert.Equal(t, "boolean", e.Responses["409"].Schema.Type)
+	assert.Equal(t, "string", e.Responses["400"].Schema.Type)
+	assert.Nil(t, e.Responses["204"].Schema)
+
+	for _, r := range e.Responses {
+		if r.Schema != nil {
+			assert.Empty(t, r.Schema.Ref)
+		}
+	}
+}
@@ -16,7 +16,10 @@ package endpoint
 
 import (
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/miketonks/swag/swagger"
 )
 
 var (
@@ -38,3 +41,13 @@ func camel(v string) string {
 
 	return strings.Join(results, "")
 }
+
+// sortedNames returns the names of the parameters in sorted order so that map based options produce stable output
+func sortedNames(params map[string]swagger.Parameter) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/miketonks/swag"
 	"github.com/miketonks/swag/endpoint"
 	"github.com/miketonks/swag/swagger"
+	"github.com/miketonks/swag/ui"
 )
 
 func handle(w http.ResponseWriter, req *http.Request) {
@@ -76,6 +77,7 @@ func main() {
 
 	enableCors := true
 	http.Handle("/swagger", api.Handler(enableCors))
+	http.Handle("/docs/", ui.Handler("/swagger", ui.Title("Swagger Petstore")))
 
 	http.ListenAndServe(":8080", nil)
 }
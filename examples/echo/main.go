@@ -15,13 +15,13 @@
 package main
 
 import (
+	"log"
 	"net/http"
-	"strings"
 
 	"github.com/labstack/echo"
 	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/echo"
 	"github.com/miketonks/swag/endpoint"
-	"github.com/miketonks/swag/swagger"
 )
 
 func handle(c echo.Context) error {
@@ -61,31 +61,9 @@ func main() {
 	)
 
 	router := echo.New()
-	api.Walk(func(path string, endpoint *swagger.Endpoint) {
-		h := endpoint.Handler.(func(c echo.Context) error)
-		path = swag.ColonPath(path)
-
-		switch strings.ToLower(endpoint.Method) {
-		case "get":
-			router.GET(path, h)
-		case "head":
-			router.HEAD(path, h)
-		case "options":
-			router.OPTIONS(path, h)
-		case "delete":
-			router.DELETE(path, h)
-		case "put":
-			router.PUT(path, h)
-		case "post":
-			router.POST(path, h)
-		case "trace":
-			router.TRACE(path, h)
-		case "patch":
-			router.PATCH(path, h)
-		case "connect":
-			router.CONNECT(path, h)
-		}
-	})
+	if err := adapter.Register(api, router); err != nil {
+		log.Fatal(err)
+	}
 
 	enableCors := true
 	router.GET("/swagger", echo.WrapHandler(api.Handler(enableCors)))
@@ -16,12 +16,13 @@ package main
 
 import (
 	"io"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/gin"
 	"github.com/miketonks/swag/endpoint"
-	"github.com/miketonks/swag/swagger"
 )
 
 func handle(c *gin.Context) {
@@ -61,12 +62,9 @@ func main() {
 	)
 
 	router := gin.New()
-	api.Walk(func(path string, endpoint *swagger.Endpoint) {
-		h := endpoint.Handler.(func(c *gin.Context))
-		path = swag.ColonPath(path)
-
-		router.Handle(endpoint.Method, path, h)
-	})
+	if err := adapter.Register(api, router); err != nil {
+		log.Fatal(err)
+	}
 
 	enableCors := true
 	router.GET("/swagger", gin.WrapH(api.Handler(enableCors)))
@@ -16,12 +16,13 @@ package main
 
 import (
 	"io"
+	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/gorilla"
 	"github.com/miketonks/swag/endpoint"
-	"github.com/miketonks/swag/swagger"
 )
 
 func handle(w http.ResponseWriter, _ *http.Request) {
@@ -61,12 +62,9 @@ func main() {
 	)
 
 	router := mux.NewRouter()
-	api.Walk(func(path string, endpoint *swagger.Endpoint) {
-		h := endpoint.Handler.(http.HandlerFunc)
-		path = swag.ColonPath(path)
-
-		router.Path(path).Methods(endpoint.Method).Handler(h)
-	})
+	if err := adapter.Register(api, router); err != nil {
+		log.Fatal(err)
+	}
 
 	enableCors := true
 	router.Path("/swagger").Methods("GET").Handler(api.Handler(enableCors))
@@ -16,12 +16,13 @@ package main
 
 import (
 	"io"
+	"log"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/miketonks/swag"
+	adapter "github.com/miketonks/swag/adapters/httprouter"
 	"github.com/miketonks/swag/endpoint"
-	"github.com/miketonks/swag/swagger"
 )
 
 func handle(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
@@ -62,11 +63,9 @@ func main() {
 	)
 
 	router := httprouter.New()
-	api.Walk(func(path string, endpoint *swagger.Endpoint) {
-		h := endpoint.Handler.(func(w http.ResponseWriter, req *http.Request, param httprouter.Params))
-		path = swag.ColonPath(path)
-		router.Handle(endpoint.Method, path, h)
-	})
+	if err := adapter.Register(api, router); err != nil {
+		log.Fatal(err)
+	}
 
 	enableCors := true
 	router.Handler("GET", "/swagger", api.Handler(enableCors))
@@ -16,6 +16,8 @@
 package swagger
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -30,41 +32,60 @@ type Object struct {
 	IsArray              bool                `json:"-"`
 	GoType               reflect.Type        `json:"-"`
 	Name                 string              `json:"-"`
+	Ref                  string              `json:"$ref,omitempty"`
 	Type                 string              `json:"type"`
+	Title                string              `json:"title,omitempty"`
+	Description          string              `json:"description,omitempty"`
 	Default              interface{}         `json:"default,omitempty"`
 	Format               string              `json:"format,omitempty"`
 	Required             []string            `json:"required,omitempty"`
 	Properties           map[string]Property `json:"properties,omitempty"`
-	AdditionalProperties bool                `json:"additionalProperties"`
+	AdditionalProperties interface{}         `json:"additionalProperties"`
+	Discriminator        string              `json:"discriminator,omitempty"`
+	AllOf                []Object            `json:"allOf,omitempty"`
+	Example              interface{}         `json:"example,omitempty"`
+
+	// Extensions holds vendor extensions, written alongside the other fields; keys must start with x-
+	Extensions map[string]interface{} `json:"-"`
+
+	// PropertyOrder, when set, lists the property names in the order they are rendered; generated definitions record
+	// their struct field order when the registry's StructFieldOrder is enabled
+	PropertyOrder []string `json:"-"`
 }
 
 // Property represents the property entity from the swagger definition
 type Property struct {
-	GoType               reflect.Type `json:"-"`
-	Type                 string       `json:"type,omitempty"`
-	Description          string       `json:"description,omitempty"`
-	Enum                 []string     `json:"enum,omitempty"`
-	Default              interface{}  `json:"default,omitempty"`
-	Format               string       `json:"format,omitempty"`
-	Pattern              string       `json:"pattern,omitempty"`
-	Ref                  string       `json:"$ref,omitempty"`
-	Example              string       `json:"example,omitempty"`
-	Items                *Items       `json:"items,omitempty"`
-	Nullable             bool         `json:"x-nullable,omitempty"`
-	MinItems             int          `json:"minItems,omitempty"`
-	MaxItems             int          `json:"maxItems,omitempty"`
-	UniqueItems          bool         `json:"uniqueItems,omitempty"`
-	MinLength            int          `json:"minLength,omitempty"`
-	MaxLength            int          `json:"maxLength,omitempty"`
-	Minimum              *int64       `json:"minimum,omitempty"`
-	Maximum              *int64       `json:"maximum,omitempty"`
-	ExclusiveMinimum     bool         `json:"exclusiveMinimum,omitempty"`
-	ExclusiveMaximum     bool         `json:"exclusiveMaximum,omitempty"`
-	AdditionalProperties interface{}  `json:"additionalProperties,omitempty"`
+	GoType               reflect.Type  `json:"-"`
+	Type                 string        `json:"type,omitempty"`
+	Title                string        `json:"title,omitempty"`
+	Description          string        `json:"description,omitempty"`
+	ReadOnly             bool          `json:"readOnly,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	EnumVarNames         []string      `json:"x-enum-varnames,omitempty"`
+	Default              interface{}   `json:"default,omitempty"`
+	Format               string        `json:"format,omitempty"`
+	Pattern              string        `json:"pattern,omitempty"`
+	Ref                  string        `json:"$ref,omitempty"`
+	Example              interface{}   `json:"example,omitempty"`
+	Items                *Items        `json:"items,omitempty"`
+	Nullable             bool          `json:"x-nullable,omitempty"`
+	MinItems             int           `json:"minItems,omitempty"`
+	MaxItems             int           `json:"maxItems,omitempty"`
+	UniqueItems          bool          `json:"uniqueItems,omitempty"`
+	MinLength            int           `json:"minLength,omitempty"`
+	MaxLength            int           `json:"maxLength,omitempty"`
+	Minimum              *float64      `json:"minimum,omitempty"`
+	Maximum              *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool          `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool          `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64      `json:"multipleOf,omitempty"`
+	AdditionalProperties interface{}   `json:"additionalProperties,omitempty"`
 }
 
 // Contact represents the contact entity from the swagger definition; used by Info
 type Contact struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
 	Email string `json:"email,omitempty"`
 }
 
@@ -129,15 +150,24 @@ func BasicSecurity() SecuritySchemeOption {
 // the location of the API key (query or header). "name" is the name of the
 // header or query parameter to be used.
 func APIKeySecurity(name, in string) SecuritySchemeOption {
+	opt, err := APIKeySecurityE(name, in)
+	if err != nil {
+		panic(err)
+	}
+	return opt
+}
+
+// APIKeySecurityE is like APIKeySecurity, but returns an error rather than panicking if "in" is invalid
+func APIKeySecurityE(name, in string) (SecuritySchemeOption, error) {
 	if in != "header" && in != "query" {
-		panic(fmt.Errorf(`APIKeySecurity "in" parameter must be one of: "header" or "query"`))
+		return nil, fmt.Errorf(`APIKeySecurity "in" parameter must be one of: "header" or "query", got %q`, in)
 	}
 
 	return func(securityScheme *SecurityScheme) {
 		securityScheme.Type = "apiKey"
 		securityScheme.Name = name
 		securityScheme.In = in
-	}
+	}, nil
 }
 
 // OAuth2Scope adds a new scope to the security scheme.
@@ -296,6 +326,16 @@ type API struct {
 	Host                string                 `json:"host,omitempty"`
 	SecurityDefinitions map[string]interface{} `json:"securityDefinitions,omitempty"`
 	Security            *SecurityRequirement   `json:"security,omitempty"`
+	Consumes            []string               `json:"consumes,omitempty"`
+	Produces            []string               `json:"produces,omitempty"`
+	ExternalDocs        *Docs                  `json:"externalDocs,omitempty"`
+
+	// Registry generates the definitions of endpoints that do not have their own registry; nil uses the package
+	// level settings
+	Registry *Registry `json:"-"`
+
+	// definitionNames holds the names given to types whose definition names collided while building the api
+	definitionNames map[reflect.Type]string
 }
 
 func (a *API) clone() *API {
@@ -310,6 +350,11 @@ func (a *API) clone() *API {
 		Host:                a.Host,
 		SecurityDefinitions: a.SecurityDefinitions,
 		Security:            a.Security,
+		Consumes:            a.Consumes,
+		Produces:            a.Produces,
+		ExternalDocs:        a.ExternalDocs,
+		Registry:            a.Registry,
+		definitionNames:     a.definitionNames,
 	}
 }
 
@@ -344,7 +389,7 @@ func (a *API) addPath(e *Endpoint) {
 	case "CONNECT":
 		v.Connect = e
 	default:
-		panic(fmt.Errorf("invalid method, %v", e.Method))
+		panic(&APIError{Err: fmt.Errorf("invalid method, %v", e.Method)})
 	}
 }
 
@@ -353,31 +398,103 @@ func (a *API) addDefinition(e *Endpoint) {
 		a.Definitions = map[string]Object{}
 	}
 
+	if a.definitionNames == nil {
+		a.definitionNames = map[reflect.Type]string{}
+	}
+
+	// the schemas of the endpoint were made without the renames of the api
+	remake := len(a.definitionNames) > 0
+	r := e.Registry
+	if r == nil && a.Registry != nil {
+		// the schemas of the endpoint were made with the package level settings
+		r = a.Registry
+		remake = true
+	}
+	r = r.orDefault().named(a.definitionNames)
+	if remake {
+		r.remakeSchemas(e)
+	}
+	renamed := len(r.definitionNames)
+
 	if e.Parameters != nil {
 		for _, p := range e.Parameters {
-			if p.Schema != nil {
-				def := define(p.Schema.Prototype)
-				for k, v := range def {
-					if _, ok := a.Definitions[k]; !ok {
-						a.Definitions[k] = v
-					}
-				}
+			for _, prototype := range r.definedPrototypes(p.Schema) {
+				a.mergeDefinitions(r, r.definitionsOf(prototype))
 			}
 		}
 	}
 
 	if e.Responses != nil {
 		for _, response := range e.Responses {
-			if response.Schema != nil {
-				def := define(response.Schema.Prototype)
-				for k, v := range def {
-					if _, ok := a.Definitions[k]; !ok {
-						a.Definitions[k] = v
-					}
-				}
+			for _, prototype := range r.definedPrototypes(response.Schema) {
+				a.mergeDefinitions(r, r.definitionsOf(prototype))
 			}
 		}
 	}
+
+	// a name collision renamed types that earlier endpoints and definitions may already refer to
+	if len(r.definitionNames) != renamed {
+		a.redefine()
+	}
+}
+
+// definedPrototypes returns the prototypes of the definitions the schema refers to; inline schemas refer to none, but
+// for the structs or interfaces their items or values refer to
+func (r *Registry) definedPrototypes(s *Schema) []interface{} {
+	if s == nil || s.Prototype == nil || s.Type == "file" {
+		return nil
+	}
+	t, ok := s.Prototype.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(s.Prototype)
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if !r.inlined(t) {
+		return []interface{}{s.Prototype}
+	}
+	if rt := referencedType(r.inspect(t, "")); rt != nil {
+		return []interface{}{rt}
+	}
+	return nil
+}
+
+func (a *API) mergeDefinitions(r *Registry, def map[string]Object) {
+	for k, v := range def {
+		if existing, ok := a.Definitions[k]; !ok {
+			a.Definitions[k] = v
+		} else {
+			r.checkCollision(existing, v)
+		}
+	}
+}
+
+// redefine generates the schemas of every endpoint, and the definitions they refer to, again; definitions that were
+// not generated from go types are kept
+func (a *API) redefine() {
+	for k, v := range a.Definitions {
+		if v.GoType != nil {
+			delete(a.Definitions, k)
+		}
+	}
+
+	a.Walk(func(_ string, e *Endpoint) {
+		r := e.Registry
+		if r == nil {
+			r = a.Registry
+		}
+		r.remakeSchemas(e)
+		a.addDefinition(e)
+	})
+}
+
+// SetRegistry sets the registry that generates the definitions of endpoints without their own registry; the
+// definitions of the endpoints already added are generated again
+func (a *API) SetRegistry(r *Registry) {
+	a.Registry = r
+	if len(a.Paths) > 0 {
+		a.redefine()
+	}
 }
 
 // AddEndpoint adds the specified endpoint to the API definition; to generate an endpoint use ```endpoint.New```
@@ -387,28 +504,76 @@ func (a *API) AddEndpoint(e *Endpoint) {
 }
 
 // Handler is a factory method that generates an http.HandlerFunc; if enableCors is true, then the handler will generate
-// cors headers
+// cors headers. The definition is served as yaml when the request accepts application/yaml or its path ends in .yaml,
+// with an ETag so that clients can revalidate with If-None-Match
 func (a *API) Handler(enableCors bool) http.HandlerFunc {
-	return func(w http.ResponseWriter, req *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
+	return a.VersionHandler(Swagger2, enableCors)
+}
 
+// VersionHandler is a factory method that generates an http.HandlerFunc serving the definition in the format of the
+// requested version, as json or, if requested, yaml; if enableCors is true, then the handler will generate cors headers
+func (a *API) VersionHandler(version Version, enableCors bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
 		if enableCors {
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, api_key, Authorization")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, PUT")
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 		}
 
+		var doc interface{} = a
+		if version != Swagger2 {
+			doc = a.ToOpenAPI(version)
+		}
+
+		contentType := "application/json"
+		var data []byte
+		var err error
+		if wantsYAML(req) {
+			contentType = "application/yaml"
+			data, err = renderYAML(doc)
+		} else {
+			data, err = json.Marshal(doc)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, err.Error())
+			return
+		}
+
+		// for swagger 2.0 json the etag matches API.Hash
+		etag := `"` + hash(data) + `"`
+		if contentType == "application/json" {
+			data = append(data, '\n')
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Vary", "Accept")
+		if matchETag(req.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
 		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
 
-		json.NewEncoder(w).Encode(a)
+// matchETag returns true if the If-None-Match header value matches etag
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
 	}
+	return false
 }
 
-// Walk invoke the callback for each endpoints defined in the swagger doc
+// Walk invoke the callback for each endpoints defined in the swagger doc, in path order
 func (a *API) Walk(callback func(path string, endpoints *Endpoint)) {
-	for rawPath, endpoints := range a.Paths {
+	for _, rawPath := range sortedKeys(a.Paths) {
 		u := path.Join(a.BasePath, rawPath)
-		endpoints.Walk(func(endpoint *Endpoint) {
+		a.Paths[rawPath].Walk(func(endpoint *Endpoint) {
 			callback(u, endpoint)
 		})
 	}
@@ -419,6 +584,21 @@ func (a *API) RenderJSON() ([]byte, error) {
 	return json.MarshalIndent(a, "", "  ")
 }
 
+// Hash returns a content hash of the swagger schema, as a hex encoded sha256 of its json encoding. The encoding is
+// canonical, so the hash only changes when the definition does
+func (a *API) Hash() (string, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+	return hash(data), nil
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // RemovePrivate function removes 'private' (where name starts with "_") parameters
 func (a *API) RemovePrivate() *API {
 	for k, definition := range a.Definitions {
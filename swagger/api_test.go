@@ -15,14 +15,17 @@
 package swagger_test
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
 
 	"path/filepath"
 
+	"github.com/miketonks/swag/endpoint"
 	"github.com/miketonks/swag/swagger"
 	"github.com/stretchr/testify/assert"
 )
@@ -178,3 +181,127 @@ func TestGoogleOAuth2(t *testing.T) {
 	assert.Equal(t, scheme.JwksURI, jwksURI)
 	assert.Equal(t, scheme.Audiences, audiences)
 }
+
+func TestHandlerETag(t *testing.T) {
+	api := openAPIFixture(t)
+	hash, err := api.Hash()
+	assert.Nil(t, err)
+	again, err := openAPIFixture(t).Hash()
+	assert.Nil(t, err)
+	assert.Equal(t, hash, again)
+
+	w := httptest.NewRecorder()
+	api.Handler(false)(w, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"`+hash+`"`, w.Header().Get("ETag"))
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	req.Header.Set("If-None-Match", `"other", `+w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	api.Handler(false)(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/swagger.yaml", nil)
+	req.Header.Set("If-None-Match", `"`+hash+`"`)
+	w = httptest.NewRecorder()
+	api.Handler(false)(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "expected yaml to have its own etag")
+}
+
+func TestAPIKeySecurityE(t *testing.T) {
+	_, err := swagger.APIKeySecurityE("Authorization", "cookie")
+	assert.EqualError(t, err, `APIKeySecurity "in" parameter must be one of: "header" or "query", got "cookie"`)
+
+	opt, err := swagger.APIKeySecurityE("Authorization", "query")
+	assert.Nil(t, err)
+	scheme := &swagger.SecurityScheme{}
+	opt(scheme)
+	assert.Equal(t, "query", scheme.In)
+}
+
+type Nested struct {
+	Pattern string `json:"pattern" pattern:"("`
+}
+
+type Outer struct {
+	Nested Nested `json:"nested"`
+}
+
+func TestMakeSchemaE(t *testing.T) {
+	_, err := swagger.MakeSchemaE(Nested{})
+	assert.EqualError(t, err, "swagger_test.Nested: field Pattern: tag pattern: error parsing regexp: missing closing ): `(`")
+
+	api := &swagger.API{}
+	err = api.AddEndpointE(&swagger.Endpoint{
+		Method:    "GET",
+		Path:      "/outer",
+		Responses: map[string]swagger.Response{"200": {Schema: &swagger.Schema{Prototype: Outer{}}}},
+	})
+	var schemaErr *swagger.SchemaError
+	assert.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, "Pattern", schemaErr.Field)
+
+	err = api.AddEndpointE(&swagger.Endpoint{Method: "FETCH", Path: "/outer"})
+	var apiErr *swagger.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.EqualError(t, err, "invalid method, FETCH")
+}
+
+type Broken string
+
+func (Broken) SwaggerEnum() []interface{} {
+	var values []interface{}
+	return values[:1]
+}
+
+func TestMakeSchemaERuntimeError(t *testing.T) {
+	defer func() {
+		_, ok := recover().(runtime.Error)
+		assert.True(t, ok, "expected the runtime error to be raised again")
+	}()
+
+	swagger.MakeSchemaE(Broken(""))
+	t.Error("expected MakeSchemaE to panic")
+}
+
+type Counter struct {
+	Count int `json:"count"`
+}
+
+func TestInlineSchemas(t *testing.T) {
+	registry := swagger.NewRegistry()
+	registry.UsePackageName = false
+	api := &swagger.API{Registry: registry}
+	api.AddEndpoint(endpoint.New("get", "/ids", "list ids",
+		endpoint.Response(http.StatusOK, []*int64{}, "ids"),
+	))
+	api.AddEndpoint(endpoint.New("get", "/counters", "list counters",
+		endpoint.Response(http.StatusOK, map[string]*Counter{}, "counters by name"),
+	))
+	api.AddEndpoint(endpoint.New("post", "/tags", "add tags",
+		endpoint.Body([]string{}, "tags", true),
+	))
+	api.AddEndpoint(endpoint.New("get", "/counters/grid", "grid of counters",
+		endpoint.Response(http.StatusOK, [][]Counter{}, "counters by row and column"),
+	))
+
+	assert.Equal(t, []string{"Counter"}, keys(api.Definitions))
+
+	data, err := json.Marshal(api.Paths["/ids"].Get.Responses["200"].Schema)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"array","items":{"type":"integer","format":"int64"}}`, string(data))
+
+	data, err = json.Marshal(api.Paths["/counters"].Get.Responses["200"].Schema)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"object","additionalProperties":{"$ref":"#/definitions/Counter","x-nullable":true}}`, string(data))
+
+	data, err = json.Marshal(api.Paths["/counters/grid"].Get.Responses["200"].Schema)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"array","items":{"type":"array","items":{"$ref":"#/definitions/Counter"}}}`, string(data))
+
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	}, api.ToOpenAPI(swagger.OpenAPI3).Paths["/tags"].Post.RequestBody.Content["application/json"].Schema)
+}
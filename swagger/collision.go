@@ -0,0 +1,84 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+)
+
+// CollisionPolicy decides what happens when different types are given the same definition name, e.g.
+// billing.Account and users.Account, which are both named Account unless UsePackageName is set
+type CollisionPolicy int
+
+const (
+	// QualifyCollisions names the colliding types after their package as well, e.g. billing_Account and
+	// users_Account, or with CollisionName if it is set; references to the types are rewritten
+	QualifyCollisions CollisionPolicy = iota
+
+	// FailOnCollision panics with an error naming the colliding types
+	FailOnCollision
+
+	// KeepFirstOnCollision keeps the definition of the type seen first and refers to it from both types
+	KeepFirstOnCollision
+)
+
+// NameCollisions is the policy applied when different types are given the same definition name
+var NameCollisions = QualifyCollisions
+
+// CollisionName, when set, names the types whose definition names collide under QualifyCollisions, in place of the
+// package qualified name
+var CollisionName func(t reflect.Type) string
+
+// nameCollision applies the NameCollisions policy of the registry to two different types, first and second, that are both named
+// name; types that are renamed are recorded in the registry
+func (r *Registry) nameCollision(name string, first, second reflect.Type) {
+	switch r.NameCollisions {
+	case KeepFirstOnCollision:
+		return
+	case FailOnCollision:
+		panic(&APIError{Err: fmt.Errorf("definition %v: %v and %v have the same name", name, first, second)})
+	}
+
+	_, firstRenamed := r.definitionNames[first]
+	_, secondRenamed := r.definitionNames[second]
+	if firstRenamed && secondRenamed {
+		panic(&APIError{
+			Err: fmt.Errorf("definition %v: %v and %v have the same name after qualifying them", name, first, second),
+		})
+	}
+
+	qualify := func(t reflect.Type) string {
+		if r.CollisionName != nil {
+			return r.CollisionName(t)
+		}
+		if path.Base(first.PkgPath()) == path.Base(second.PkgPath()) {
+			return t.PkgPath() + "." + t.Name()
+		}
+		return path.Base(t.PkgPath()) + "." + t.Name()
+	}
+	r.definitionNames[first] = qualify(first)
+	r.definitionNames[second] = qualify(second)
+}
+
+// checkCollision applies the NameCollisions policy if the definition already named name is of a different type than
+// obj; returns true if it did
+func (r *Registry) checkCollision(existing, obj Object) bool {
+	if existing.GoType == nil || obj.GoType == nil || existing.GoType == obj.GoType {
+		return false
+	}
+	r.nameCollision(obj.Name, existing.GoType, obj.GoType)
+	return true
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Contact struct {
+	Phone string `json:"phone"`
+}
+
+type Directory struct {
+	Owner    swagger.Contact `json:"owner"`
+	Contacts []Contact       `json:"contacts"`
+}
+
+func TestQualifyCollisions(t *testing.T) {
+	usePackageName(t, false)
+
+	api := swag.New(swag.Endpoints(
+		endpoint.New("get", "/contact", "Get contact", endpoint.Response(200, Contact{}, "ok")),
+		endpoint.New("get", "/directory", "Get directory", endpoint.Response(200, Directory{}, "ok")),
+	))
+	assert.Empty(t, api.Validate())
+
+	assert.Equal(t, []string{"Directory", "swagger_Contact", "swagger_test_Contact"}, keys(api.Definitions))
+	assert.Equal(t, "#/definitions/swagger_test_Contact", api.Paths["/contact"].Get.Responses["200"].Schema.Ref)
+
+	directory := api.Definitions["Directory"]
+	assert.Equal(t, "#/definitions/swagger_Contact", directory.Properties["owner"].Ref)
+	assert.Equal(t, "#/definitions/swagger_test_Contact", directory.Properties["contacts"].Items.Ref)
+}
+
+func TestCollisionsPerAPI(t *testing.T) {
+	usePackageName(t, false)
+
+	first := swag.New(swag.Endpoints(
+		endpoint.New("get", "/directory", "Get directory", endpoint.Response(200, Directory{}, "ok")),
+	))
+	assert.Equal(t, []string{"Directory", "swagger_Contact", "swagger_test_Contact"}, keys(first.Definitions))
+
+	// the renames of the first api do not carry over to the next
+	second := swag.New(swag.Endpoints(
+		endpoint.New("get", "/contact", "Get contact", endpoint.Response(200, Contact{}, "ok")),
+	))
+	assert.Equal(t, []string{"Contact"}, keys(second.Definitions))
+	assert.Equal(t, "#/definitions/Contact", second.Paths["/contact"].Get.Responses["200"].Schema.Ref)
+	assert.Equal(t, "#/definitions/swagger_test_Contact", first.Definitions["Directory"].Properties["contacts"].Items.Ref)
+}
+
+type License struct {
+	Key string `json:"key"`
+}
+
+func TestFailOnCollision(t *testing.T) {
+	usePackageName(t, false)
+	swagger.NameCollisions = swagger.FailOnCollision
+	defer func() { swagger.NameCollisions = swagger.QualifyCollisions }()
+
+	assert.PanicsWithError(t, "definition License: swagger_test.License and swagger.License have the same name", func() {
+		swag.New(swag.Endpoints(
+			endpoint.New("get", "/license", "Get license", endpoint.Response(200, License{}, "ok")),
+			endpoint.New("get", "/info", "Get info", endpoint.Response(200, swagger.License{}, "ok")),
+		))
+	})
+}
+
+type Info struct {
+	Version int `json:"version"`
+}
+
+func TestCollisionName(t *testing.T) {
+	usePackageName(t, false)
+	swagger.CollisionName = func(t reflect.Type) string {
+		if t.PkgPath() == reflect.TypeOf(swagger.Info{}).PkgPath() {
+			return "ApiInfo"
+		}
+		return t.Name()
+	}
+	defer func() { swagger.CollisionName = nil }()
+
+	api := swag.New(swag.Endpoints(
+		endpoint.New("get", "/info", "Get info", endpoint.Response(200, Info{}, "ok")),
+		endpoint.New("get", "/api", "Get api info", endpoint.Response(200, swagger.Info{}, "ok")),
+	))
+	assert.Contains(t, api.Definitions, "Info")
+	assert.Contains(t, api.Definitions, "ApiInfo")
+	assert.Equal(t, "#/definitions/ApiInfo", api.Paths["/api"].Get.Responses["200"].Schema.Ref)
+}
+
+type Account struct {
+	ID string `json:"id"`
+}
+
+func (Account) SwaggerName() string {
+	return "UserAccount"
+}
+
+type Ledger struct {
+	Accounts []Account `json:"accounts"`
+}
+
+func TestSwaggerName(t *testing.T) {
+	usePackageName(t, false)
+
+	api := swag.New(swag.Endpoints(
+		endpoint.New("get", "/ledger", "Get ledger", endpoint.Response(200, Ledger{}, "ok")),
+	))
+	assert.Contains(t, api.Definitions, "UserAccount")
+	assert.Equal(t, "#/definitions/UserAccount", api.Definitions["Ledger"].Properties["accounts"].Items.Ref)
+}
+
+// usePackageName sets swagger.UsePackageName for the duration of the test
+func usePackageName(t *testing.T, v bool) {
+	previous := swagger.UsePackageName
+	swagger.UsePackageName = v
+	t.Cleanup(func() { swagger.UsePackageName = previous })
+}
+
+func keys(m map[string]swagger.Object) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,210 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// docComments holds the doc comments loaded for the default registry
+var docComments = map[string]string{}
+
+// LoadDocComments parses the Go sources in each of the specified package directories and uses the doc comments of
+// struct types and their fields as the descriptions of the definitions generated afterwards by the default registry;
+// description and doc tags take precedence
+func LoadDocComments(dirs ...string) error {
+	return defaultRegistry().LoadDocComments(dirs...)
+}
+
+// LoadDocComments parses the Go sources in each of the specified package directories and uses the doc comments of
+// struct types and their fields as the descriptions of the definitions generated afterwards; description and doc
+// tags take precedence. Types are matched by import path and type name, the import path being worked out from the
+// enclosing go.mod or GOPATH
+func (r *Registry) LoadDocComments(dirs ...string) error {
+	comments := map[string]string{}
+	for _, dir := range dirs {
+		pkgPath, err := importPath(dir)
+		if err != nil {
+			return err
+		}
+
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		for name, pkg := range pkgs {
+			prefix := pkgPath
+			switch {
+			case name == "main":
+				prefix = "main"
+			case strings.HasSuffix(name, "_test"):
+				prefix += "_test"
+			}
+
+			for _, file := range pkg.Files {
+				for _, decl := range file.Decls {
+					gen, ok := decl.(*ast.GenDecl)
+					if !ok || gen.Tok != token.TYPE {
+						continue
+					}
+
+					for _, spec := range gen.Specs {
+						ts := spec.(*ast.TypeSpec)
+						key := prefix + "." + ts.Name.Name
+
+						doc := ts.Doc
+						if doc == nil && len(gen.Specs) == 1 {
+							doc = gen.Doc
+						}
+						addDocComment(comments, key, doc)
+
+						st, ok := ts.Type.(*ast.StructType)
+						if !ok {
+							continue
+						}
+						for _, field := range st.Fields.List {
+							doc := field.Doc
+							if doc == nil {
+								doc = field.Comment
+							}
+							for _, fieldName := range field.Names {
+								addDocComment(comments, key+"."+fieldName.Name, doc)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for key, text := range comments {
+		r.docComments[key] = text
+	}
+	r.reset()
+	return nil
+}
+
+func addDocComment(comments map[string]string, key string, doc *ast.CommentGroup) {
+	if text := strings.TrimSpace(doc.Text()); text != "" {
+		comments[key] = text
+	}
+}
+
+// importPath returns the import path of the package in dir, from the module path of the enclosing go.mod or, failing
+// that, the location of dir in GOPATH
+func importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for root := abs; ; root = filepath.Dir(root) {
+		if module, ok, err := modulePath(filepath.Join(root, "go.mod")); err != nil {
+			return "", err
+		} else if ok {
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(module, filepath.ToSlash(rel)), nil
+		}
+		if filepath.Dir(root) == root {
+			break
+		}
+	}
+
+	pkg, err := build.ImportDir(abs, build.FindOnly)
+	if err != nil {
+		return "", err
+	}
+	if pkg.ImportPath == "" || pkg.ImportPath == "." {
+		return "", fmt.Errorf("cannot determine the import path of %v", dir)
+	}
+	return pkg.ImportPath, nil
+}
+
+// modulePath reads the module path declared in the go.mod file, if it exists
+func modulePath(filename string) (string, bool, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		if module, err := strconv.Unquote(fields[1]); err == nil {
+			return module, true, nil
+		}
+		return fields[1], true, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", false, err
+	}
+	return "", false, fmt.Errorf("no module path in %v", filename)
+}
+
+// typeDoc returns the doc comment of the named type t, if loaded
+func (r *Registry) typeDoc(t reflect.Type) string {
+	return r.docComments[docKey(t)]
+}
+
+// fieldDoc returns the description of a struct field from its description or doc tag or, failing that, its doc comment
+func (r *Registry) fieldDoc(t reflect.Type, field reflect.StructField) string {
+	if v := field.Tag.Get("description"); v != "" {
+		return v
+	}
+	if v := field.Tag.Get("doc"); v != "" {
+		return v
+	}
+	if len(r.docComments) == 0 {
+		return ""
+	}
+	return r.docComments[docKey(t)+"."+field.Name]
+}
+
+// docKey returns the import path and name of the named type t, without any type arguments
+func docKey(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+
+	name := t.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return t.PkgPath() + "." + name
+}
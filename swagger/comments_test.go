@@ -0,0 +1,79 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Documented is a type with doc comments
+type Documented struct {
+	// Name is the name
+	Name string `json:"name"`
+	Age  int    `json:"age"` // Age in years
+	Tag  string `json:"tag" description:"from the tag"`
+	Doc  string `json:"doc" doc:"from the doc tag"`
+	None string `json:"none"`
+}
+
+func TestDescriptionTags(t *testing.T) {
+	obj := defineObject(Documented{})
+	assert.Equal(t, "", obj.Description)
+	assert.Equal(t, "", obj.Properties["name"].Description)
+	assert.Equal(t, "from the tag", obj.Properties["tag"].Description)
+	assert.Equal(t, "from the doc tag", obj.Properties["doc"].Description)
+}
+
+func TestLoadDocComments(t *testing.T) {
+	defer func() { docComments = map[string]string{} }()
+	assert.Nil(t, LoadDocComments("."))
+
+	obj := defineObject(Documented{})
+	assert.Equal(t, "Documented is a type with doc comments", obj.Description)
+	assert.Equal(t, "Name is the name", obj.Properties["name"].Description)
+	assert.Equal(t, "Age in years", obj.Properties["age"].Description)
+	assert.Equal(t, "from the tag", obj.Properties["tag"].Description)
+	assert.Equal(t, "from the doc tag", obj.Properties["doc"].Description)
+	assert.Equal(t, "", obj.Properties["none"].Description)
+
+	assert.NotNil(t, LoadDocComments("testdata/missing"))
+}
+
+func TestLoadDocCommentsImportPath(t *testing.T) {
+	defer func() { docComments = map[string]string{} }()
+
+	// same package and type names, another import path
+	assert.Nil(t, LoadDocComments("testdata/docs"))
+	assert.Equal(t, "Documented shares its package and type name with the Documented type of the swagger package",
+		docComments["github.com/miketonks/swag/swagger/testdata/docs.Documented"])
+
+	obj := defineObject(Documented{})
+	assert.Equal(t, "", obj.Description)
+	assert.Equal(t, "", obj.Properties["name"].Description)
+}
+
+func TestRegistryLoadDocComments(t *testing.T) {
+	r := NewRegistry()
+	assert.Nil(t, r.LoadDocComments("."))
+
+	obj := r.defineObject(Documented{})
+	assert.Equal(t, "Documented is a type with doc comments", obj.Description)
+	assert.Equal(t, "Name is the name", obj.Properties["name"].Description)
+
+	// the default registry is not affected
+	assert.Empty(t, docComments)
+	assert.Equal(t, "", defineObject(Documented{}).Description)
+}
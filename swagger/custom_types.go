@@ -1,7 +1,11 @@
 package swagger
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"net/url"
 	"reflect"
 	"strings"
 	"time"
@@ -63,11 +67,9 @@ func (t *Time) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("\"%s\"", t.Time.Format(tmLayout))), nil
 }
 
-var customTypes map[reflect.Type]Property
+var customTypes = map[reflect.Type]Property{}
 
 func init() {
-	customTypes = map[reflect.Type]Property{}
-
 	RegisterCustomType(time.Time{}, Property{
 		Type:   "string",
 		Format: "date-time",
@@ -87,6 +89,59 @@ func init() {
 		Type:   "string",
 		Format: "uuid",
 	})
+
+	// standard library types whose json form differs from their go kind; types implementing encoding.TextMarshaler,
+	// e.g. net.IP or big.Float, are strings without registration
+	RegisterCustomType([]byte{}, Property{
+		Type:   "string",
+		Format: "byte",
+	})
+
+	RegisterCustomType(time.Duration(0), Property{
+		Type:        "integer",
+		Format:      "int64",
+		Description: "duration in nanoseconds",
+	})
+
+	RegisterCustomType(json.Number(""), Property{
+		Type: "number",
+	})
+
+	RegisterCustomType(big.Int{}, Property{
+		Type: "integer",
+	})
+}
+
+// RegisterSQLNullTypes registers the database/sql null types, e.g. sql.NullString, as nullable scalars, for services
+// that marshal them as their value or null; encoding/json writes them as objects, so they are not registered by
+// default
+func RegisterSQLNullTypes() {
+	defaultRegistry().RegisterSQLNullTypes()
+}
+
+// RegisterSQLNullTypes registers the database/sql null types as nullable scalars for the definitions generated with
+// the registry; see the package level RegisterSQLNullTypes
+func (r *Registry) RegisterSQLNullTypes() {
+	r.RegisterCustomType(sql.NullString{}, Property{Type: "string", Nullable: true})
+	r.RegisterCustomType(sql.NullInt64{}, Property{Type: "integer", Format: "int64", Nullable: true})
+	r.RegisterCustomType(sql.NullInt32{}, Property{Type: "integer", Format: "int32", Nullable: true})
+	r.RegisterCustomType(sql.NullInt16{}, Property{Type: "integer", Format: "int32", Nullable: true})
+	r.RegisterCustomType(sql.NullByte{}, Property{Type: "integer", Format: "int32", Nullable: true})
+	r.RegisterCustomType(sql.NullFloat64{}, Property{Type: "number", Format: "double", Nullable: true})
+	r.RegisterCustomType(sql.NullBool{}, Property{Type: "boolean", Nullable: true})
+	r.RegisterCustomType(sql.NullTime{}, Property{Type: "string", Format: "date-time", Nullable: true})
+}
+
+// RegisterURLType registers url.URL as a uri string, for services that marshal it as its String; encoding/json writes
+// it as an object, so it is not registered by default
+func RegisterURLType() {
+	defaultRegistry().RegisterURLType()
+}
+
+// RegisterURLType registers url.URL as a uri string for the definitions generated with the registry; see the package
+// level RegisterURLType
+func (r *Registry) RegisterURLType() {
+	r.RegisterCustomType(url.URL{}, Property{Type: "string", Format: "uri"})
 }
 
 // RegisterCustomType maps a reflect.Type to a pre-defined Property. This can be
@@ -105,7 +160,79 @@ func init() {
 // For example: registering time.Time will also apply to *time.Time, unless
 // *time.Time has also been registered.
 func RegisterCustomType(v interface{}, p Property) {
+	defaultRegistry().RegisterCustomType(v, p)
+}
+
+// RegisterCustomType maps a reflect.Type to a pre-defined Property for the definitions generated with the registry;
+// see the package level RegisterCustomType
+func (r *Registry) RegisterCustomType(v interface{}, p Property) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
 	t := reflect.TypeOf(v)
 	p.GoType = t
-	customTypes[t] = p
+	r.customTypes[t] = p
+	r.reset()
+}
+
+// TypeResolver describes the go types it recognizes, e.g. every instantiation of a generic wrapper or every type
+// implementing an interface, as a Property; ok is false for the types it leaves to the other resolvers and to
+// reflection. The objects are the definitions the property refers to, named after the type when their Name is
+// empty. A property with a GoType other than t but no Type or Ref describes t as that type, e.g. an Optional[T] as a
+// nullable T, keeping its Nullable and Description; resolvers are not called for pointers, which are described as
+// nullable elements.
+type TypeResolver func(t reflect.Type) (p Property, objects []Object, ok bool)
+
+var resolvers []TypeResolver
+
+// RegisterTypeResolver adds a resolver for the types RegisterCustomType can't match exactly. Resolvers are consulted
+// in registration order, after the custom types and the types providing their own property.
+//
+//    RegisterTypeResolver(func(t reflect.Type) (Property, []Object, bool) {
+//      if !t.Implements(moneyType) {
+//        return Property{}, nil, false
+//      }
+//      return Property{Ref: "#/definitions/Money"}, []Object{moneyObject}, true
+//    })
+func RegisterTypeResolver(resolver TypeResolver) {
+	defaultRegistry().RegisterTypeResolver(resolver)
+}
+
+// RegisterTypeResolver adds a resolver for the definitions generated with the registry; see the package level
+// RegisterTypeResolver
+func (r *Registry) RegisterTypeResolver(resolver TypeResolver) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	*r.resolvers = append(*r.resolvers, resolver)
+	r.reset()
+}
+
+// resolve returns the property and definitions of t from the first resolver recognizing it
+func (r *Registry) resolve(t reflect.Type) (Property, []Object, bool) {
+	if t.Kind() == reflect.Ptr {
+		return Property{}, nil, false
+	}
+	for _, resolver := range *r.resolvers {
+		p, objects, ok := resolver(t)
+		if !ok {
+			continue
+		}
+		if p.GoType == nil {
+			p.GoType = t
+		}
+		for i, obj := range objects {
+			if obj.Name == "" {
+				objects[i].Name = r.makeName(t)
+				objects[i].GoType = t
+			}
+		}
+		return p, objects, true
+	}
+	return Property{}, nil, false
+}
+
+// delegates reports whether the resolved property describes its type as another type
+func (p Property) delegates(t reflect.Type) bool {
+	return p.GoType != t && p.Type == "" && p.Ref == "" && p.Items == nil && p.AdditionalProperties == nil
 }
@@ -0,0 +1,118 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+// SwaggerDescriber is implemented by types that provide the description of their own definition
+type SwaggerDescriber interface {
+	SwaggerDescription() string
+}
+
+// SwaggerSchemaCustomizer is implemented by types that adjust their generated definition, e.g. to set a title, an
+// example payload or extensions, to add required fields or to override properties
+type SwaggerSchemaCustomizer interface {
+	SwaggerSchema(o *Object)
+}
+
+// SwaggerNamer is implemented by types that choose the name of their own definition
+type SwaggerNamer interface {
+	SwaggerName() string
+}
+
+// SwaggerExampleProvider is implemented by types that provide an example value of their own definition
+type SwaggerExampleProvider interface {
+	SwaggerExample() interface{}
+}
+
+// SwaggerPropertyProvider is implemented by types that provide their own property in place of the one generated by
+// reflection; it is the equivalent of RegisterCustomType without the global registration
+type SwaggerPropertyProvider interface {
+	SwaggerProperty() Property
+}
+
+// SwaggerEnumProvider is implemented by types whose values are limited to a set, e.g. the constants of a const block;
+// it is the equivalent of RegisterEnum without the global registration
+type SwaggerEnumProvider interface {
+	SwaggerEnum() []interface{}
+}
+
+// SwaggerEnumNamer is implemented by a SwaggerEnumProvider to name its values, in the same order, for client generators
+type SwaggerEnumNamer interface {
+	SwaggerEnumNames() []string
+}
+
+// providedProperty returns the property provided by t, or by *t, if it implements SwaggerPropertyProvider
+func providedProperty(t reflect.Type) (Property, bool) {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return Property{}, false
+	}
+
+	provider, ok := reflect.New(t).Interface().(SwaggerPropertyProvider)
+	if !ok {
+		return Property{}, false
+	}
+
+	p := provider.SwaggerProperty()
+	p.GoType = t
+	return p, true
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// marshalsText reports whether encoding/json writes t as a string because t, or *t, implements encoding.TextMarshaler
+// but not json.Marshaler
+func marshalsText(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+
+	pt := reflect.PtrTo(t)
+	return pt.Implements(textMarshalerType) && !pt.Implements(jsonMarshalerType)
+}
+
+// declaredName returns the name chosen by t, or by *t, if it implements SwaggerNamer
+func declaredName(t reflect.Type) (string, bool) {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return "", false
+	}
+
+	namer, ok := reflect.New(t).Interface().(SwaggerNamer)
+	if !ok {
+		return "", false
+	}
+	return namer.SwaggerName(), true
+}
+
+// customize applies the SwaggerDescriber, SwaggerExampleProvider and SwaggerSchemaCustomizer implementations of t, or *t, to its definition
+func customize(t reflect.Type, obj *Object) {
+	v := reflect.New(t).Interface()
+
+	if describer, ok := v.(SwaggerDescriber); ok {
+		obj.Description = describer.SwaggerDescription()
+	}
+	if provider, ok := v.(SwaggerExampleProvider); ok {
+		obj.Example = provider.SwaggerExample()
+	}
+	if customizer, ok := v.(SwaggerSchemaCustomizer); ok {
+		customizer.SwaggerSchema(obj)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Money struct {
+	Cents int64
+}
+
+func (Money) SwaggerProperty() swagger.Property {
+	return swagger.Property{Type: "string", Pattern: `^\d+\.\d\d$`}
+}
+
+type Invoice struct {
+	ID       string `json:"id"`
+	Total    Money  `json:"total"`
+	Discount *Money `json:"discount"`
+}
+
+func (*Invoice) SwaggerDescription() string {
+	return "An invoice"
+}
+
+func (Invoice) SwaggerSchema(o *swagger.Object) {
+	o.Title = "Invoice"
+	o.Example = map[string]interface{}{"id": "inv-1", "total": "9.99"}
+	o.Extensions = map[string]interface{}{"x-internal": true}
+	o.Required = append(o.Required, "id")
+
+	id := o.Properties["id"]
+	id.Format = "uuid"
+	o.Properties["id"] = id
+}
+
+func TestCustomizers(t *testing.T) {
+	usePackageName(t, false)
+	api := swag.New(swag.Endpoints(endpoint.New("get", "/invoice", "Get invoice",
+		endpoint.Response(200, Invoice{}, "ok"),
+	)))
+
+	assert.NotContains(t, api.Definitions, "Money", "expected provided properties not to be defined")
+
+	data, err := json.Marshal(api.Definitions["Invoice"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"title": "Invoice",
+		"description": "An invoice",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"total": {"type": "string", "pattern": "^\\d+\\.\\d\\d$"},
+			"discount": {"type": "string", "pattern": "^\\d+\\.\\d\\d$", "x-nullable": true}
+		},
+		"additionalProperties": false,
+		"example": {"id": "inv-1", "total": "9.99"},
+		"x-internal": true
+	}`, string(data))
+
+	var loaded swagger.Object
+	assert.Nil(t, json.Unmarshal(data, &loaded))
+	assert.Equal(t, map[string]interface{}{"x-internal": true}, loaded.Extensions)
+}
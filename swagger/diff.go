@@ -0,0 +1,505 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Change describes a single difference between two versions of an API
+type Change struct {
+	Breaking bool   `json:"breaking"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "BREAKING"
+	}
+	return fmt.Sprintf("%-12s %v: %v", kind, c.Location, c.Message)
+}
+
+// Changes is the list of differences between two versions of an API
+type Changes []Change
+
+// Breaking returns only the breaking changes
+func (c Changes) Breaking() Changes {
+	var breaking Changes
+	for _, change := range c {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// String renders the changes as human readable text, one change per line
+func (c Changes) String() string {
+	lines := make([]string, 0, len(c))
+	for _, change := range c {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// differ accumulates the changes found while comparing two APIs
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) breaking(location, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Breaking: true, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) compatible(location, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+// usage tells whether a schema is read from requests, written to responses, or both. Narrowing the values a schema
+// allows breaks the clients writing requests, while widening them breaks the clients reading responses
+type usage struct {
+	request, response bool
+}
+
+var (
+	inRequests  = usage{request: true}
+	inResponses = usage{response: true}
+)
+
+// narrowed records a change that rejects values allowed before; it breaks requests
+func (d *differ) narrowed(location string, u usage, format string, args ...interface{}) {
+	if u.request {
+		d.breaking(location, format, args...)
+	} else {
+		d.compatible(location, format, args...)
+	}
+}
+
+// widened records a change that allows values rejected before; it breaks responses
+func (d *differ) widened(location string, u usage, format string, args ...interface{}) {
+	if u.response {
+		d.breaking(location, format, args...)
+	} else {
+		d.compatible(location, format, args...)
+	}
+}
+
+// Diff compares two versions of an API and classifies each change as breaking or non-breaking for existing clients
+func Diff(before, after *API) Changes {
+	d := &differ{}
+	usages := definitionUsages(before, after)
+
+	if before.BasePath != after.BasePath {
+		d.breaking("basePath", "changed from %v to %v", before.BasePath, after.BasePath)
+	}
+
+	for _, p := range sortedKeys(before.Paths) {
+		old := endpointsByMethod(before.Paths[p])
+		var current map[string]*Endpoint
+		if e, ok := after.Paths[p]; ok {
+			current = endpointsByMethod(e)
+		}
+
+		for _, method := range sortedKeys(old) {
+			location := "paths." + p + "." + method
+			e, ok := current[method]
+			if !ok {
+				d.breaking(location, "endpoint removed")
+				continue
+			}
+			d.endpoint(location, old[method], e)
+		}
+	}
+	for _, p := range sortedKeys(after.Paths) {
+		var old map[string]*Endpoint
+		if e, ok := before.Paths[p]; ok {
+			old = endpointsByMethod(e)
+		}
+		for _, method := range sortedKeys(endpointsByMethod(after.Paths[p])) {
+			if _, ok := old[method]; !ok {
+				d.compatible("paths."+p+"."+method, "endpoint added")
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(before.Definitions) {
+		location := "definitions." + name
+		def, ok := after.Definitions[name]
+		if !ok {
+			d.breaking(location, "definition removed")
+			continue
+		}
+		u, ok := usages[name]
+		if !ok {
+			// unused definitions may be used by clients either way
+			u = usage{request: true, response: true}
+		}
+		d.object(location, u, before.Definitions[name], def)
+	}
+	for _, name := range sortedKeys(after.Definitions) {
+		if _, ok := before.Definitions[name]; !ok {
+			d.compatible("definitions."+name, "definition added")
+		}
+	}
+
+	return d.changes
+}
+
+// definitionUsages works out, for each definition referred to by the endpoints of the apis, whether it is used in
+// requests, responses or both; the definitions it refers to, and those extending it with allOf, are used likewise
+func definitionUsages(apis ...*API) map[string]usage {
+	usages := map[string]usage{}
+	var pending []string
+	use := func(u usage, names ...string) {
+		for _, name := range names {
+			current := usages[name]
+			merged := usage{request: current.request || u.request, response: current.response || u.response}
+			if merged != current {
+				usages[name] = merged
+				pending = append(pending, name)
+			}
+		}
+	}
+
+	for _, a := range apis {
+		for _, endpoints := range a.Paths {
+			for _, e := range endpointsByMethod(endpoints) {
+				use(inRequests, definitionRefs(e.Parameters)...)
+				use(inResponses, definitionRefs(e.Responses)...)
+			}
+		}
+	}
+
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		for _, a := range apis {
+			def, ok := a.Definitions[name]
+			if !ok {
+				continue
+			}
+			use(usages[name], definitionRefs(def)...)
+			for _, other := range sortedKeys(a.Definitions) {
+				for _, parent := range a.Definitions[other].AllOf {
+					if parent.Ref == makeRef(name) {
+						use(usages[name], other)
+					}
+				}
+			}
+		}
+	}
+	return usages
+}
+
+// definitionRefs returns the names of the definitions v refers to
+func definitionRefs(v interface{}) []string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var names []string
+	walkRefs("", doc, func(_, ref string) {
+		if name, err := url.QueryUnescape(strings.TrimPrefix(ref, "#/definitions/")); err == nil && name != ref {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+func endpointsByMethod(e *Endpoints) map[string]*Endpoint {
+	m := map[string]*Endpoint{}
+	if e != nil {
+		e.Walk(func(endpoint *Endpoint) {
+			m[strings.ToLower(endpoint.Method)] = endpoint
+		})
+	}
+	return m
+}
+
+func (d *differ) endpoint(location string, before, after *Endpoint) {
+	if !before.Deprecated && after.Deprecated {
+		d.compatible(location, "endpoint deprecated")
+	}
+
+	params := func(e *Endpoint) map[string]Parameter {
+		m := map[string]Parameter{}
+		for _, p := range e.Parameters {
+			m[p.In+" parameter "+p.Name] = p
+		}
+		return m
+	}
+	old, current := params(before), params(after)
+
+	for _, name := range sortedKeys(old) {
+		p, ok := current[name]
+		if !ok {
+			d.compatible(location, "%v removed", name)
+			continue
+		}
+		d.parameter(location, name, old[name], p)
+	}
+	for _, name := range sortedKeys(current) {
+		if _, ok := old[name]; ok {
+			continue
+		}
+		if current[name].Required {
+			d.breaking(location, "required %v added", name)
+		} else {
+			d.compatible(location, "optional %v added", name)
+		}
+	}
+
+	for _, code := range sortedKeys(before.Responses) {
+		r, ok := after.Responses[code]
+		if !ok {
+			d.breaking(location, "response %v removed", code)
+			continue
+		}
+		d.response(location+".responses."+code, before.Responses[code], r)
+	}
+	for _, code := range sortedKeys(after.Responses) {
+		if _, ok := before.Responses[code]; !ok {
+			d.compatible(location, "response %v added", code)
+		}
+	}
+}
+
+func (d *differ) response(location string, before, after Response) {
+	d.schema(location, inResponses, before.Schema, after.Schema)
+
+	for _, name := range sortedKeys(before.Headers) {
+		h, ok := after.Headers[name]
+		if !ok {
+			d.breaking(location, "header %v removed", name)
+			continue
+		}
+		d.scalar(location, "header "+name, before.Headers[name].Type, h.Type, before.Headers[name].Format, h.Format)
+	}
+	for _, name := range sortedKeys(after.Headers) {
+		if _, ok := before.Headers[name]; !ok {
+			d.compatible(location, "header %v added", name)
+		}
+	}
+}
+
+func (d *differ) parameter(location, name string, before, after Parameter) {
+	if !before.Required && after.Required {
+		d.breaking(location, "%v became required", name)
+	} else if before.Required && !after.Required {
+		d.compatible(location, "%v became optional", name)
+	}
+
+	d.scalar(location, name, before.Type, after.Type, before.Format, after.Format)
+	d.enum(location, inRequests, name, before.Enum, after.Enum)
+	d.items(location, inRequests, name+" items", before.Items, after.Items)
+	if collectionFormat(before) != collectionFormat(after) {
+		d.breaking(location, "%v collectionFormat changed from %v to %v", name, collectionFormat(before), collectionFormat(after))
+	}
+	d.schema(location, inRequests, before.Schema, after.Schema)
+}
+
+// collectionFormat returns the collection format of an array parameter, which defaults to csv
+func collectionFormat(p Parameter) string {
+	if p.Type != "array" {
+		return ""
+	}
+	if p.CollectionFormat == "" {
+		return "csv"
+	}
+	return p.CollectionFormat
+}
+
+func (d *differ) schema(location string, u usage, before, after *Schema) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		d.compatible(location, "schema added")
+	case after == nil:
+		d.breaking(location, "schema removed")
+	default:
+		d.scalar(location, "schema", before.Type, after.Type, before.Format, after.Format)
+		d.ref(location, "schema", before.Ref, after.Ref)
+		d.items(location, u, "schema items", before.Items, after.Items)
+		d.additionalProperties(location, u, "schema", before.AdditionalProperties, after.AdditionalProperties)
+	}
+}
+
+func (d *differ) object(location string, u usage, before, after Object) {
+	d.scalar(location, "definition", before.Type, after.Type, before.Format, after.Format)
+
+	required := map[string]bool{}
+	for _, name := range before.Required {
+		required[name] = true
+	}
+	stillRequired := map[string]bool{}
+	for _, name := range after.Required {
+		stillRequired[name] = true
+		if !required[name] {
+			d.narrowed(location, u, "property %v became required", name)
+		}
+	}
+	for _, name := range before.Required {
+		if _, ok := after.Properties[name]; ok && !stillRequired[name] {
+			d.widened(location, u, "property %v became optional", name)
+		}
+	}
+
+	for _, name := range sortedKeys(before.Properties) {
+		p, ok := after.Properties[name]
+		if !ok {
+			if required[name] {
+				// clients reading the property relied on it being there
+				d.breaking(location, "required property %v removed", name)
+			} else {
+				d.narrowed(location, u, "property %v removed", name)
+			}
+			continue
+		}
+		d.property(location+".properties."+name, u, before.Properties[name], p)
+	}
+	for _, name := range sortedKeys(after.Properties) {
+		if _, ok := before.Properties[name]; !ok {
+			d.compatible(location, "property %v added", name)
+		}
+	}
+
+	d.additionalProperties(location, u, "definition", before.AdditionalProperties, after.AdditionalProperties)
+}
+
+func (d *differ) property(location string, u usage, before, after Property) {
+	d.scalar(location, "property", before.Type, after.Type, before.Format, after.Format)
+	d.ref(location, "property", before.Ref, after.Ref)
+	d.enum(location, u, "property", before.Enum, after.Enum)
+	d.items(location, u, "property items", before.Items, after.Items)
+	d.additionalProperties(location, u, "property", before.AdditionalProperties, after.AdditionalProperties)
+}
+
+func (d *differ) items(location string, u usage, name string, before, after *Items) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil || after == nil:
+		d.breaking(location, "%v changed", name)
+	default:
+		d.scalar(location, name, before.Type, after.Type, before.Format, after.Format)
+		d.ref(location, name, before.Ref, after.Ref)
+		d.enum(location, u, name, before.Enum, after.Enum)
+		d.items(location, u, name+" items", before.Items, after.Items)
+		d.additionalProperties(location, u, name, before.AdditionalProperties, after.AdditionalProperties)
+	}
+}
+
+// additionalProperties compares whether, and which, properties beyond those listed are allowed. Schemas of map
+// values are compared as properties; allowing any additional property is compatible, as clients ignore those they
+// don't know, but forbidding them or constraining their values narrows the schema
+func (d *differ) additionalProperties(location string, u usage, name string, before, after interface{}) {
+	if b, ok := before.(*Property); ok {
+		if a, ok := after.(*Property); ok {
+			d.property(location+".additionalProperties", u, *b, *a)
+			return
+		}
+	}
+
+	// the less restrictive of false, a schema and true
+	openness := func(v interface{}) int {
+		switch ap := v.(type) {
+		case *Property, map[string]interface{}:
+			return 1
+		case bool:
+			if ap {
+				return 2
+			}
+		}
+		return 0
+	}
+	switch b, a := openness(before), openness(after); {
+	case b == a:
+	case a < b:
+		d.narrowed(location, u, "%v additionalProperties restricted", name)
+	case b == 1:
+		// values that had to match a schema may now be anything
+		d.widened(location, u, "%v additionalProperties no longer constrained", name)
+	default:
+		d.compatible(location, "%v additionalProperties allowed", name)
+	}
+}
+
+func (d *differ) scalar(location, name, beforeType, afterType, beforeFormat, afterFormat string) {
+	if beforeType != afterType {
+		d.breaking(location, "%v type changed from %v to %v", name, describe(beforeType), describe(afterType))
+	} else if beforeFormat != afterFormat {
+		d.breaking(location, "%v format changed from %v to %v", name, describe(beforeFormat), describe(afterFormat))
+	}
+}
+
+func (d *differ) ref(location, name, before, after string) {
+	if before != after {
+		d.breaking(location, "%v $ref changed from %v to %v", name, describe(before), describe(after))
+	}
+}
+
+// enum compares the allowed values; an empty enum allows any value, so adding one narrows the schema and removing
+// one widens it
+func (d *differ) enum(location string, u usage, name string, before, after []interface{}) {
+	if len(after) == 0 {
+		if len(before) > 0 {
+			d.widened(location, u, "%v enum removed", name)
+		}
+		return
+	}
+	if len(before) == 0 {
+		d.narrowed(location, u, "%v enum added", name)
+		return
+	}
+
+	if removed := missing(before, after); len(removed) > 0 {
+		d.narrowed(location, u, "%v enum values removed: %v", name, strings.Join(removed, ", "))
+	}
+	if added := missing(after, before); len(added) > 0 {
+		d.widened(location, u, "%v enum values added: %v", name, strings.Join(added, ", "))
+	}
+}
+
+// missing returns the values of a that are not in b, compared as printed since loaded documents hold every number as
+// a float64
+func missing(a, b []interface{}) []string {
+	found := map[string]bool{}
+	for _, v := range b {
+		found[fmt.Sprint(v)] = true
+	}
+
+	var values []string
+	for _, v := range a {
+		if !found[fmt.Sprint(v)] {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	return values
+}
+
+func describe(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
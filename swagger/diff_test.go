@@ -0,0 +1,172 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := openAPIFixture(t)
+	before.Paths["/pet/{petId}"].Get.Parameters[1].Enum = []interface{}{"yes", "no"}
+
+	after := openAPIFixture(t)
+	after.Paths["/pet/{petId}"].Get.Parameters[1].Enum = []interface{}{"yes", "maybe"}
+	after.Paths["/pet/{petId}"].Get.Parameters = append(after.Paths["/pet/{petId}"].Get.Parameters,
+		swagger.Parameter{In: "query", Name: "limit", Type: "integer", Required: true},
+		swagger.Parameter{In: "query", Name: "offset", Type: "integer"},
+	)
+	after.Paths["/pet/{petId}/image"] = nil
+	delete(after.Paths["/pet/{petId}"].Get.Responses, "200")
+	after.AddEndpoint(endpoint.New("delete", "/pet/{petId}", "Deletes a pet",
+		endpoint.Path("petId", "integer", "int64", "Pet id to delete"),
+		endpoint.Response(http.StatusNoContent, "", "deleted"),
+	))
+
+	animal := after.Definitions["Animal"]
+	animal.Required = []string{"name"}
+	id := animal.Properties["id"]
+	id.Type = "string"
+	id.Format = ""
+	animal.Properties["id"] = id
+	after.Definitions["Animal"] = animal
+
+	changes := swagger.Diff(before, after)
+	assert.Equal(t, swagger.Changes{
+		{Breaking: true, Location: "paths./pet/{petId}.get", Message: "query parameter verbose enum values removed: no"},
+		{Location: "paths./pet/{petId}.get", Message: "query parameter verbose enum values added: maybe"},
+		{Breaking: true, Location: "paths./pet/{petId}.get", Message: "required query parameter limit added"},
+		{Location: "paths./pet/{petId}.get", Message: "optional query parameter offset added"},
+		{Breaking: true, Location: "paths./pet/{petId}.get", Message: "response 200 removed"},
+		{Breaking: true, Location: "paths./pet/{petId}/image.post", Message: "endpoint removed"},
+		{Location: "paths./pet/{petId}.delete", Message: "endpoint added"},
+		{Breaking: true, Location: "definitions.Animal", Message: "property name became required"},
+		{Breaking: true, Location: "definitions.Animal.properties.id", Message: "property type changed from integer to string"},
+	}, changes)
+	assert.Len(t, changes.Breaking(), 6)
+
+	assert.True(t, strings.HasPrefix(changes.String(),
+		"BREAKING     paths./pet/{petId}.get: query parameter verbose enum values removed: no\n"+
+			"non-breaking paths./pet/{petId}.get: query parameter verbose enum values added: maybe\n"))
+
+	data, err := json.Marshal(changes[:1])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"breaking":true,"location":"paths./pet/{petId}.get","message":"query parameter verbose enum values removed: no"}]`, string(data))
+
+	assert.Empty(t, swagger.Diff(openAPIFixture(t), openAPIFixture(t)))
+}
+
+func TestDiffCollectionFormat(t *testing.T) {
+	api := func(options ...endpoint.ParamOption) *swagger.API {
+		return swag.New(swag.Endpoints(endpoint.New("get", "/pet", "List pets",
+			endpoint.Query("tags", "array", "", "tags", false, append(options, endpoint.Items("string", ""))...),
+		)))
+	}
+
+	assert.Empty(t, swagger.Diff(api(), api(endpoint.CollectionFormat("csv"))))
+	assert.Equal(t, swagger.Changes{
+		{Breaking: true, Location: "paths./pet.get", Message: "query parameter tags collectionFormat changed from csv to multi"},
+	}, swagger.Diff(api(), api(endpoint.CollectionFormat("multi"))))
+}
+
+type Purchase struct {
+	Item  string `json:"item" enum:"book,pen"`
+	Count int    `json:"count"`
+}
+
+type Receipt struct {
+	ID     string `json:"id" required:"true"`
+	Status string `json:"status" enum:"paid,refunded"`
+	Note   string `json:"note"`
+	Total  int    `json:"total"`
+}
+
+func TestDiffDirections(t *testing.T) {
+	usePackageName(t, false)
+
+	api := func() *swagger.API {
+		return swag.New(swag.Endpoints(endpoint.New("post", "/purchase", "Buy something",
+			endpoint.Body(Purchase{}, "purchase", true),
+			endpoint.Response(http.StatusOK, Receipt{}, "receipt",
+				endpoint.Header("X-Request-ID", "string", "", "request id"),
+			),
+		)))
+	}
+
+	before, after := api(), api()
+	purchase := after.Definitions["Purchase"]
+	purchase.Required = []string{"count"}
+	item := purchase.Properties["item"]
+	item.Enum = []interface{}{"book", "pen", "ink"}
+	purchase.Properties["item"] = item
+	after.Definitions["Purchase"] = purchase
+
+	receipt := after.Definitions["Receipt"]
+	receipt.Required = []string{"total"}
+	status := receipt.Properties["status"]
+	status.Enum = []interface{}{"paid", "refunded", "void"}
+	receipt.Properties["status"] = status
+	delete(receipt.Properties, "note")
+	receipt.AdditionalProperties = true
+	after.Definitions["Receipt"] = receipt
+
+	response := after.Paths["/purchase"].Post.Responses["200"]
+	response.Headers = map[string]swagger.Header{"X-Trace-ID": {Type: "string"}}
+	after.Paths["/purchase"].Post.Responses["200"] = response
+
+	assert.Equal(t, swagger.Changes{
+		{Breaking: true, Location: "paths./purchase.post.responses.200", Message: "header X-Request-ID removed"},
+		{Location: "paths./purchase.post.responses.200", Message: "header X-Trace-ID added"},
+		{Breaking: true, Location: "definitions.Purchase", Message: "property count became required"},
+		{Location: "definitions.Purchase.properties.item", Message: "property enum values added: ink"},
+		{Location: "definitions.Receipt", Message: "property total became required"},
+		{Breaking: true, Location: "definitions.Receipt", Message: "property id became optional"},
+		{Location: "definitions.Receipt", Message: "property note removed"},
+		{Breaking: true, Location: "definitions.Receipt.properties.status", Message: "property enum values added: void"},
+		{Location: "definitions.Receipt", Message: "definition additionalProperties allowed"},
+	}, swagger.Diff(before, after))
+
+	// the same changes in the other direction
+	assert.Equal(t, swagger.Changes{
+		{Breaking: true, Location: "paths./purchase.post.responses.200", Message: "header X-Trace-ID removed"},
+		{Location: "paths./purchase.post.responses.200", Message: "header X-Request-ID added"},
+		{Location: "definitions.Purchase", Message: "property count became optional"},
+		{Breaking: true, Location: "definitions.Purchase.properties.item", Message: "property enum values removed: ink"},
+		{Location: "definitions.Receipt", Message: "property id became required"},
+		{Breaking: true, Location: "definitions.Receipt", Message: "property total became optional"},
+		{Location: "definitions.Receipt.properties.status", Message: "property enum values removed: void"},
+		{Location: "definitions.Receipt", Message: "property note added"},
+		{Location: "definitions.Receipt", Message: "definition additionalProperties restricted"},
+	}, swagger.Diff(after, before))
+}
+
+func TestDiffMapValues(t *testing.T) {
+	api := func(values string) *swagger.API {
+		return &swagger.API{Definitions: map[string]swagger.Object{
+			"Inventory": {Type: "object", AdditionalProperties: &swagger.Property{Type: values}},
+		}}
+	}
+
+	assert.Equal(t, swagger.Changes{
+		{Breaking: true, Location: "definitions.Inventory.additionalProperties", Message: "property type changed from integer to string"},
+	}, swagger.Diff(api("integer"), api("string")))
+}
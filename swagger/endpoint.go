@@ -19,30 +19,37 @@ import "encoding/json"
 
 // Items represents items from the swagger doc
 type Items struct {
-	Type                 string      `json:"type,omitempty"`
-	Default              interface{} `json:"default,omitempty"`
-	Format               string      `json:"format,omitempty"`
-	Enum                 []string    `json:"enum,omitempty"`
-	Ref                  string      `json:"$ref,omitempty"`
-	MinItems             int         `json:"minItems,omitempty"`
-	MaxItems             int         `json:"maxItems,omitempty"`
-	UniqueItems          bool        `json:"uniqueItems,omitempty"`
-	MinLength            int         `json:"minLength,omitempty"`
-	MaxLength            int         `json:"maxLength,omitempty"`
-	Minimum              *int64      `json:"minimum,omitempty"`
-	Maximum              *int64      `json:"maximum,omitempty"`
-	ExclusiveMinimum     bool        `json:"exclusiveMinimum,omitempty"`
-	ExclusiveMaximum     bool        `json:"exclusiveMaximum,omitempty"`
-	Pattern              string      `json:"pattern,omitempty"`
-	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	Type                 string        `json:"type,omitempty"`
+	Default              interface{}   `json:"default,omitempty"`
+	Format               string        `json:"format,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	EnumVarNames         []string      `json:"x-enum-varnames,omitempty"`
+	Ref                  string        `json:"$ref,omitempty"`
+	Items                *Items        `json:"items,omitempty"`
+	MinItems             int           `json:"minItems,omitempty"`
+	MaxItems             int           `json:"maxItems,omitempty"`
+	UniqueItems          bool          `json:"uniqueItems,omitempty"`
+	MinLength            int           `json:"minLength,omitempty"`
+	MaxLength            int           `json:"maxLength,omitempty"`
+	Minimum              *float64      `json:"minimum,omitempty"`
+	Maximum              *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool          `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool          `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64      `json:"multipleOf,omitempty"`
+	Pattern              string        `json:"pattern,omitempty"`
+	AdditionalProperties interface{}   `json:"additionalProperties,omitempty"`
 }
 
 // Schema represents a schema from the swagger doc
 type Schema struct {
-	Type      string      `json:"type,omitempty"`
-	Items     *Items      `json:"items,omitempty"`
-	Ref       string      `json:"$ref,omitempty"`
-	Prototype interface{} `json:"-"`
+	Type                 string              `json:"type,omitempty"`
+	Format               string              `json:"format,omitempty"`
+	Items                *Items              `json:"items,omitempty"`
+	Ref                  string              `json:"$ref,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	Properties           map[string]Property `json:"properties,omitempty"`
+	AdditionalProperties interface{}         `json:"additionalProperties,omitempty"`
+	Prototype            interface{}         `json:"-"`
 }
 
 // Header represents a response header
@@ -54,35 +61,43 @@ type Header struct {
 
 // Response represents a response from the swagger doc
 type Response struct {
-	Description string            `json:"description,omitempty"`
-	Schema      *Schema           `json:"schema,omitempty"`
-	Headers     map[string]Header `json:"headers,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Schema      *Schema                `json:"schema,omitempty"`
+	Headers     map[string]Header      `json:"headers,omitempty"`
+	Examples    map[string]interface{} `json:"examples,omitempty"`
 }
 
 // Parameter represents a parameter from the swagger doc
 type Parameter struct {
-	In                   string      `json:"in,omitempty"`
-	Name                 string      `json:"name,omitempty"`
-	Description          string      `json:"description,omitempty"`
-	Required             bool        `json:"required"`
-	Schema               *Schema     `json:"schema,omitempty"`
-	Type                 string      `json:"type,omitempty"`
-	Items                *Items      `json:"items,omitempty"`
-	Default              interface{} `json:"default,omitempty"`
-	Format               string      `json:"format,omitempty"`
-	Enum                 []string    `json:"enum,omitempty"`
-	Nullable             bool        `json:"x-nullable,omitempty"`
-	Pattern              string      `json:"pattern,omitempty"`
-	MinItems             int         `json:"minItems,omitempty"`
-	MaxItems             int         `json:"maxItems,omitempty"`
-	UniqueItems          bool        `json:"uniqueItems,omitempty"`
-	MaxLength            int         `json:"maxLength,omitempty"`
-	MinLength            int         `json:"minLength,omitempty"`
-	Minimum              *int64      `json:"minimum,omitempty"`
-	Maximum              *int64      `json:"maximum,omitempty"`
-	ExclusiveMinimum     bool        `json:"exclusiveMinimum,omitempty"`
-	ExclusiveMaximum     bool        `json:"exclusiveMaximum,omitempty"`
-	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	In                   string        `json:"in,omitempty"`
+	Name                 string        `json:"name,omitempty"`
+	Description          string        `json:"description,omitempty"`
+	Required             bool          `json:"required"`
+	AllowEmptyValue      bool          `json:"allowEmptyValue,omitempty"`
+	Schema               *Schema       `json:"schema,omitempty"`
+	Type                 string        `json:"type,omitempty"`
+	Items                *Items        `json:"items,omitempty"`
+	CollectionFormat     string        `json:"collectionFormat,omitempty"`
+	Default              interface{}   `json:"default,omitempty"`
+	Format               string        `json:"format,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	EnumVarNames         []string      `json:"x-enum-varnames,omitempty"`
+	Nullable             bool          `json:"x-nullable,omitempty"`
+	Pattern              string        `json:"pattern,omitempty"`
+	MinItems             int           `json:"minItems,omitempty"`
+	MaxItems             int           `json:"maxItems,omitempty"`
+	UniqueItems          bool          `json:"uniqueItems,omitempty"`
+	MaxLength            int           `json:"maxLength,omitempty"`
+	MinLength            int           `json:"minLength,omitempty"`
+	Minimum              *float64      `json:"minimum,omitempty"`
+	Maximum              *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool          `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool          `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64      `json:"multipleOf,omitempty"`
+	AdditionalProperties interface{}   `json:"additionalProperties,omitempty"`
+
+	// Example is written as the x-example extension, as swagger 2.0 has no parameter examples
+	Example interface{} `json:"x-example,omitempty"`
 }
 
 // Endpoint represents an endpoint from the swagger doc
@@ -96,10 +111,15 @@ type Endpoint struct {
 	Produces    []string            `json:"produces,omitempty"`
 	Consumes    []string            `json:"consumes,omitempty"`
 	Handler     interface{}         `json:"-"`
+	Middleware  []interface{}       `json:"-"`
 	Parameters  []Parameter         `json:"parameters,omitempty"`
 	Responses   map[string]Response `json:"responses,omitempty"`
 	Deprecated  bool                `json:"deprecated,omitempty"`
 
+	// Registry generates the schemas of the body and responses and the definitions they refer to; nil uses the
+	// registry of the API
+	Registry *Registry `json:"-"`
+
 	// swagger spec requires security to be an array of objects
 	Security *SecurityRequirement `json:"security,omitempty"`
 }
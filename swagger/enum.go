@@ -0,0 +1,141 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// enum holds the values of an enum type as encoding/json writes them, and optionally the names of their constants
+type enum struct {
+	values []interface{}
+	names  []string
+}
+
+var enums = map[reflect.Type]enum{}
+
+// RegisterEnum registers the values a type is limited to, e.g. the constants of a const block, so that every field,
+// array item and map value of the type is described with them. The names, when given, are the names of the constants
+// in the same order; they are written as x-enum-varnames for client generators.
+//
+//	RegisterEnum(Status(0), []interface{}{Active, Suspended}, "Active", "Suspended")
+//
+// Values are written as encoding/json writes them, so a type implementing encoding.TextMarshaler gets string values.
+// Types can also list their values themselves by implementing SwaggerEnumProvider.
+func RegisterEnum(v interface{}, values []interface{}, names ...string) {
+	defaultRegistry().RegisterEnum(v, values, names...)
+}
+
+// RegisterEnum registers the values of an enum type for the definitions generated with the registry; see the package
+// level RegisterEnum
+func (r *Registry) RegisterEnum(v interface{}, values []interface{}, names ...string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	t := reflect.TypeOf(v)
+	e, err := newEnum(values, names)
+	if err != nil {
+		panic(&SchemaError{Type: t, Err: fmt.Errorf("RegisterEnum: %v", err)})
+	}
+	r.enums[t] = e
+	r.reset()
+}
+
+// EnumOf returns the values and the names of the values of an enum type, registered with RegisterEnum or provided by
+// the type; values is nil for other types
+func EnumOf(v interface{}) (values []interface{}, names []string) {
+	return defaultRegistry().EnumOf(v)
+}
+
+// EnumOf returns the values and the names of the values of an enum type for the registry; see the package level EnumOf
+func (r *Registry) EnumOf(v interface{}) (values []interface{}, names []string) {
+	r = r.orDefault()
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	e, _ := r.enumOf(t)
+	return e.values, e.names
+}
+
+// enumOf returns the enum registered for t, or provided by t
+func (r *Registry) enumOf(t reflect.Type) (enum, bool) {
+	if t == nil {
+		return enum{}, false
+	}
+	if e, ok := r.enums[t]; ok {
+		return e, true
+	}
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return enum{}, false
+	}
+
+	provider, ok := reflect.New(t).Interface().(SwaggerEnumProvider)
+	if !ok {
+		return enum{}, false
+	}
+	var names []string
+	if namer, ok := provider.(SwaggerEnumNamer); ok {
+		names = namer.SwaggerEnumNames()
+	}
+	e, err := newEnum(provider.SwaggerEnum(), names)
+	if err != nil {
+		panic(&SchemaError{Type: t, Err: fmt.Errorf("SwaggerEnum: %v", err)})
+	}
+	return e, true
+}
+
+func newEnum(values []interface{}, names []string) (enum, error) {
+	if len(names) > 0 && len(names) != len(values) {
+		return enum{}, fmt.Errorf("got %v names for %v values", len(names), len(values))
+	}
+
+	e := enum{names: names}
+	for _, v := range values {
+		value, err := jsonValue(v)
+		if err != nil {
+			return enum{}, err
+		}
+		e.values = append(e.values, value)
+	}
+	return e, nil
+}
+
+// jsonValue returns v as encoding/json writes it, keeping integers as int64
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		return n.Float64()
+	}
+	return value, nil
+}
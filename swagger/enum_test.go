@@ -0,0 +1,138 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Priority int
+
+const (
+	Low Priority = iota + 1
+	Medium
+	High
+)
+
+func (Priority) SwaggerEnum() []interface{} {
+	return []interface{}{Low, Medium, High}
+}
+
+func (Priority) SwaggerEnumNames() []string {
+	return []string{"Low", "Medium", "High"}
+}
+
+type Color string
+
+const (
+	Red   Color = "red"
+	Green Color = "green"
+)
+
+type Weekday int
+
+const (
+	Monday Weekday = iota
+	Tuesday
+)
+
+func (d Weekday) MarshalText() ([]byte, error) {
+	return []byte([]string{"mon", "tue"}[d]), nil
+}
+
+func (Weekday) SwaggerEnum() []interface{} {
+	return []interface{}{Monday, Tuesday}
+}
+
+type Ticket struct {
+	Priority   Priority         `json:"priority"`
+	Escalation []Priority       `json:"escalation"`
+	Labels     map[string]Color `json:"labels"`
+	Color      *Color           `json:"color"`
+	Urgent     Priority         `json:"urgent" enum:"3"`
+	Level      int              `json:"level" enum:"1, 2, 3"`
+	Score      float64          `json:"score" validate:"oneof=0.5 1"`
+	Day        Weekday          `json:"day"`
+}
+
+func TestEnums(t *testing.T) {
+	r := swagger.NewRegistry()
+	r.UsePackageName = false
+	r.RegisterEnum(Color(""), []interface{}{Red, Green}, "Red", "Green")
+
+	api := swag.New(
+		swag.Endpoints(endpoint.New("post", "/ticket", "Open a ticket",
+			endpoint.Query("priority", "integer", "", "filter by priority", false, endpoint.EnumOf(Priority(0))),
+			endpoint.Body(Ticket{}, "ticket", true),
+		)),
+		swag.Registry(r),
+	)
+
+	props := api.Definitions["Ticket"].Properties
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, props["priority"].Enum)
+	assert.Equal(t, []string{"Low", "Medium", "High"}, props["priority"].EnumVarNames)
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, props["escalation"].Items.Enum)
+	assert.Equal(t, []string{"Low", "Medium", "High"}, props["escalation"].Items.EnumVarNames)
+	assert.Equal(t, []interface{}{"red", "green"}, props["labels"].AdditionalProperties.(*swagger.Property).Enum)
+	assert.Equal(t, []interface{}{"red", "green"}, props["color"].Enum)
+	assert.Equal(t, []string{"Red", "Green"}, props["color"].EnumVarNames)
+	assert.Equal(t, []interface{}{int64(3)}, props["urgent"].Enum)
+	assert.Nil(t, props["urgent"].EnumVarNames)
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, props["level"].Enum)
+	assert.Equal(t, []interface{}{0.5, 1.0}, props["score"].Enum)
+	assert.Equal(t, "string", props["day"].Type)
+	assert.Equal(t, []interface{}{"mon", "tue"}, props["day"].Enum)
+
+	param := api.Paths["/ticket"].Post.Parameters[0]
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, param.Enum)
+	assert.Equal(t, []string{"Low", "Medium", "High"}, param.EnumVarNames)
+
+	data, err := json.Marshal(props["priority"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"integer","format":"int32","enum":[1,2,3],"x-enum-varnames":["Low","Medium","High"]}`,
+		string(data))
+
+	values, names := swagger.EnumOf(Color(""))
+	assert.Nil(t, values, "expected enums registered with a registry to stay there")
+	assert.Nil(t, names)
+
+	assert.Panics(t, func() { r.RegisterEnum(Color(""), []interface{}{Red, Green}, "Red") })
+
+	_, err = swag.NewE(func(*swag.Builder) { r.RegisterEnum(Color(""), []interface{}{Red, Green}, "Red") })
+	var schemaErr *swagger.SchemaError
+	if assert.ErrorAs(t, err, &schemaErr) {
+		assert.Equal(t, reflect.TypeOf(Color("")), schemaErr.Type)
+	}
+}
+
+func TestDiffEnums(t *testing.T) {
+	before := openAPIFixture(t)
+	before.Paths["/pet/{petId}"].Get.Parameters[1].Enum = []interface{}{int64(1), int64(2)}
+
+	// loaded documents hold numbers as float64
+	after := openAPIFixture(t)
+	after.Paths["/pet/{petId}"].Get.Parameters[1].Enum = []interface{}{float64(1), float64(3)}
+
+	assert.Equal(t, swagger.Changes{
+		{Breaking: true, Location: "paths./pet/{petId}.get", Message: "query parameter verbose enum values removed: 2"},
+		{Location: "paths./pet/{petId}.get", Message: "query parameter verbose enum values added: 3"},
+	}, swagger.Diff(before, after))
+}
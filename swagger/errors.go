@@ -0,0 +1,105 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SchemaError reports a go type that a definition cannot be generated for, e.g. because of an invalid tag value
+type SchemaError struct {
+	// Type is the struct type, if known
+	Type reflect.Type
+
+	// Field is the name of the struct field, if known
+	Field string
+
+	// Tag is the name of the struct tag, if the error is caused by its value
+	Tag string
+
+	Err error
+}
+
+func (e *SchemaError) Error() string {
+	msg := e.Err.Error()
+	if e.Tag != "" {
+		msg = fmt.Sprintf("tag %v: %v", e.Tag, msg)
+	}
+	if e.Field != "" {
+		msg = fmt.Sprintf("field %v: %v", e.Field, msg)
+	}
+	if e.Type != nil {
+		msg = fmt.Sprintf("%v: %v", e.Type, msg)
+	}
+	return msg
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+func tagError(tag string, err error) *SchemaError {
+	return &SchemaError{Tag: tag, Err: err}
+}
+
+// APIError reports an endpoint that cannot be added to an api, e.g. because of an invalid method or different types
+// with the same definition name
+type APIError struct {
+	Err error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// catch recovers from a panic with a *SchemaError or *APIError, raised while generating definitions, and stores it in
+// err; other panics, e.g. runtime errors, are raised again. The generation code panics on errors so the panicking API
+// remains a thin wrapper
+func catch(err *error) {
+	if v := recover(); v != nil {
+		switch e := v.(type) {
+		case *SchemaError:
+			*err = e
+		case *APIError:
+			*err = e
+		default:
+			panic(v)
+		}
+	}
+}
+
+// MakeSchemaE is like MakeSchema, but returns an error, e.g. a *SchemaError for an invalid tag value, rather than
+// panicking
+func MakeSchemaE(prototype interface{}) (*Schema, error) {
+	return defaultRegistry().MakeSchemaE(prototype)
+}
+
+// MakeSchemaE is like MakeSchema, but returns an error rather than panicking
+func (r *Registry) MakeSchemaE(prototype interface{}) (s *Schema, err error) {
+	defer catch(&err)
+	return r.MakeSchema(prototype), nil
+}
+
+// AddEndpointE is like AddEndpoint, but returns an error, an *APIError for an invalid method or a *SchemaError for an
+// invalid tag value, rather than panicking; the api may be left with part of the endpoint added
+func (a *API) AddEndpointE(e *Endpoint) (err error) {
+	defer catch(&err)
+	a.AddEndpoint(e)
+	return nil
+}
@@ -0,0 +1,378 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load parses a swagger 2.0 document, in either json or yaml format, into an API
+func Load(data []byte) (*API, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return LoadJSON(data)
+	}
+	return LoadYAML(data)
+}
+
+// LoadFile reads and parses the swagger 2.0 document stored in filename
+func LoadFile(filename string) (*API, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %v: %w", filename, err)
+	}
+	return api, nil
+}
+
+// LoadJSON parses a swagger 2.0 json document into an API; fields the API can't represent are reported as errors
+// rather than dropped
+func LoadJSON(data []byte) (*API, error) {
+	api := &API{}
+	if err := json.Unmarshal(data, api); err != nil {
+		return nil, err
+	}
+
+	kept, err := json.Marshal(api)
+	if err != nil {
+		return nil, err
+	}
+	var before, after interface{}
+	if err := json.Unmarshal(data, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(kept, &after); err != nil {
+		return nil, err
+	}
+	if location := lostField("", before, after); location != "" {
+		return nil, fmt.Errorf("unsupported field %v", location)
+	}
+	return api, nil
+}
+
+// lostField returns the location of the first non empty field of before that is missing from after, the same
+// document after loading and rendering it, or "" if none is
+func lostField(location string, before, after interface{}) string {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, _ := after.(map[string]interface{})
+		for _, k := range sortedKeys(b) {
+			if isEmpty(b[k]) {
+				continue
+			}
+			field := k
+			if location != "" {
+				field = location + "." + k
+			}
+			v, ok := a[k]
+			if !ok {
+				return field
+			}
+			if lost := lostField(field, b[k], v); lost != "" {
+				return lost
+			}
+		}
+	case []interface{}:
+		a, _ := after.([]interface{})
+		for i, item := range b {
+			field := fmt.Sprintf("%v[%v]", location, i)
+			if i >= len(a) {
+				return field
+			}
+			if lost := lostField(field, item, a[i]); lost != "" {
+				return lost
+			}
+		}
+	}
+	return ""
+}
+
+// isEmpty reports whether a decoded json value is the zero value of its type, which is omitted when rendered
+func isEmpty(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	case string:
+		return value == ""
+	case bool:
+		return !value
+	case float64:
+		return value == 0
+	}
+	return false
+}
+
+// LoadYAML parses a swagger 2.0 yaml document into an API
+func LoadYAML(data []byte) (*API, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(normalizeYAML(doc))
+	if err != nil {
+		return nil, err
+	}
+	return LoadJSON(data)
+}
+
+// normalizeYAML converts the maps produced by yaml, which may have non-string keys such as response codes, into
+// maps that can be encoded as json
+func normalizeYAML(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = normalizeYAML(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeYAML(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// UnmarshalJSON deserializes an API, restoring the path and method of each endpoint and the concrete type of each
+// security definition. Shared parameters and responses can't be represented by an API, so they are reported as errors
+// rather than dropped
+func (a *API) UnmarshalJSON(data []byte) error {
+	type api API
+	v := struct {
+		*api
+		SecurityDefinitions map[string]json.RawMessage `json:"securityDefinitions,omitempty"`
+		Parameters          map[string]json.RawMessage `json:"parameters,omitempty"`
+		Responses           map[string]json.RawMessage `json:"responses,omitempty"`
+	}{api: (*api)(a)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Parameters) > 0 || len(v.Responses) > 0 {
+		return fmt.Errorf("shared parameters and responses are not supported")
+	}
+
+	for p, endpoints := range a.Paths {
+		if endpoints == nil {
+			continue
+		}
+		setMethod := func(e *Endpoint, method string) {
+			if e != nil {
+				e.Path = p
+				e.Method = method
+			}
+		}
+		setMethod(endpoints.Delete, "DELETE")
+		setMethod(endpoints.Head, "HEAD")
+		setMethod(endpoints.Get, "GET")
+		setMethod(endpoints.Options, "OPTIONS")
+		setMethod(endpoints.Post, "POST")
+		setMethod(endpoints.Put, "PUT")
+		setMethod(endpoints.Patch, "PATCH")
+		setMethod(endpoints.Trace, "TRACE")
+		setMethod(endpoints.Connect, "CONNECT")
+	}
+
+	a.SecurityDefinitions = nil
+	for name, raw := range v.SecurityDefinitions {
+		scheme, err := unmarshalSecurityScheme(raw)
+		if err != nil {
+			return fmt.Errorf("securityDefinitions %v: %w", name, err)
+		}
+		if a.SecurityDefinitions == nil {
+			a.SecurityDefinitions = map[string]interface{}{}
+		}
+		a.SecurityDefinitions[name] = scheme
+	}
+
+	return nil
+}
+
+// unmarshalSecurityScheme decodes a security definition into a SecurityScheme or GoogleSecurityScheme; definitions
+// with other extensions are kept as generic maps so that no information is lost
+func unmarshalSecurityScheme(data []byte) (interface{}, error) {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	google := false
+	for k := range fields {
+		switch {
+		case strings.HasPrefix(k, "x-google-"):
+			google = true
+		case strings.HasPrefix(k, "x-"):
+			return fields, nil
+		}
+	}
+
+	if google {
+		scheme := GoogleSecurityScheme{}
+		err := json.Unmarshal(data, &scheme)
+		return scheme, err
+	}
+
+	scheme := SecurityScheme{}
+	err := json.Unmarshal(data, &scheme)
+	return scheme, err
+}
+
+// UnmarshalJSON deserializes a SecurityRequirement; an empty list disables security
+func (s *SecurityRequirement) UnmarshalJSON(data []byte) error {
+	var requirements []map[string][]string
+	if err := json.Unmarshal(data, &requirements); err != nil {
+		return err
+	}
+
+	s.Requirements = nil
+	s.DisableSecurity = requirements != nil && len(requirements) == 0
+	if len(requirements) > 0 {
+		s.Requirements = requirements
+	}
+	return nil
+}
+
+// UnmarshalJSON deserializes an Object, including its extensions; a schema valued additionalProperties is decoded
+// into a *Property
+func (o *Object) UnmarshalJSON(data []byte) error {
+	type object Object
+	v := struct {
+		*object
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}{object: (*object)(o)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	o.Extensions = nil
+	for k, value := range fields {
+		if strings.HasPrefix(k, "x-") {
+			if o.Extensions == nil {
+				o.Extensions = map[string]interface{}{}
+			}
+			o.Extensions[k] = value
+		}
+	}
+
+	ap, err := additionalProperties(v.AdditionalProperties)
+	if err != nil {
+		return err
+	}
+	o.AdditionalProperties = ap
+	return nil
+}
+
+// UnmarshalJSON deserializes a Property; schema valued additionalProperties are decoded into a *Property
+func (p *Property) UnmarshalJSON(data []byte) error {
+	type property Property
+	v := struct {
+		*property
+		AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	}{property: (*property)(p)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	ap, err := additionalProperties(v.AdditionalProperties)
+	if err != nil {
+		return err
+	}
+	p.AdditionalProperties = ap
+	return nil
+}
+
+// additionalProperties decodes the value of additionalProperties: a *Property for a schema, otherwise the value as is
+func additionalProperties(data json.RawMessage) (interface{}, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if trimmed[0] == '{' {
+		ap := &Property{}
+		if err := json.Unmarshal(trimmed, ap); err != nil {
+			return nil, err
+		}
+		return ap, nil
+	}
+
+	var ap interface{}
+	if err := json.Unmarshal(trimmed, &ap); err != nil {
+		return nil, err
+	}
+	return ap, nil
+}
+
+// UnmarshalJSON deserializes a Parameter; references to shared parameters are not supported, and are reported
+// rather than dropped
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type parameter Parameter
+	v := struct {
+		*parameter
+		Ref string `json:"$ref"`
+	}{parameter: (*parameter)(p)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Ref != "" {
+		return fmt.Errorf("unsupported parameter reference %v", v.Ref)
+	}
+	return nil
+}
+
+// UnmarshalJSON deserializes a Response; references to shared responses are not supported, and are reported rather
+// than dropped
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	v := struct {
+		*response
+		Ref string `json:"$ref"`
+	}{response: (*response)(r)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Ref != "" {
+		return fmt.Errorf("unsupported response reference %v", v.Ref)
+	}
+	return nil
+}
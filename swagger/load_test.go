@@ -0,0 +1,194 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRoundTrip(t *testing.T) {
+	api := openAPIFixture(t)
+	expected, err := api.RenderJSON()
+	assert.Nil(t, err)
+
+	loaded, err := swagger.Load(expected)
+	assert.Nil(t, err)
+
+	actual, err := loaded.RenderJSON()
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+
+	e := loaded.Paths["/pet/{petId}"].Get
+	assert.Equal(t, "GET", e.Method)
+	assert.Equal(t, "/pet/{petId}", e.Path)
+	assert.IsType(t, swagger.SecurityScheme{}, loaded.SecurityDefinitions["basic"])
+}
+
+func TestLoadYAML(t *testing.T) {
+	api, err := swagger.LoadFile("testdata/petstore.yaml")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "2.0", api.Swagger)
+	assert.Equal(t, "Swagger Petstore", api.Info.Title)
+	assert.Equal(t, []string{"https"}, api.Schemes)
+	assert.Equal(t, "http://swagger.io", api.Tags[0].Docs.URL)
+
+	get := api.Paths["/pet/{petId}"].Get
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "/pet/{petId}", get.Path)
+	assert.Equal(t, "int64", get.Parameters[0].Format)
+	assert.Equal(t, "#/definitions/Pet", get.Responses["200"].Schema.Ref)
+	assert.Equal(t, []map[string][]string{{"api_key": {}}}, get.Security.Requirements)
+	assert.True(t, api.Paths["/pet/{petId}"].Delete.Security.DisableSecurity)
+
+	assert.Equal(t, "header", api.SecurityDefinitions["api_key"].(swagger.SecurityScheme).In)
+	assert.Equal(t, "https://accounts.google.com", api.SecurityDefinitions["google"].(swagger.GoogleSecurityScheme).Issuer)
+	assert.Equal(t, "foo", api.SecurityDefinitions["custom"].(map[string]interface{})["x-custom-foo"])
+
+	pet := api.Definitions["Pet"]
+	assert.Equal(t, []string{"name"}, pet.Required)
+	assert.Equal(t, "doggie", pet.Properties["name"].Example)
+	assert.Equal(t, float64(3), pet.Properties["age"].Example)
+	assert.Equal(t, &swagger.Property{Type: "string"}, pet.Properties["labels"].AdditionalProperties)
+	assert.Equal(t, &swagger.Property{Type: "string"}, api.Definitions["Category"].AdditionalProperties)
+}
+
+func TestLoadLossless(t *testing.T) {
+	doc := `{
+  "swagger": "2.0",
+  "info": {"title": "Store", "version": "1.0.0"},
+  "consumes": ["application/xml"],
+  "produces": ["application/xml"],
+  "externalDocs": {"description": "Find out more", "url": "http://swagger.io"},
+  "paths": {
+    "/inventory": {
+      "get": {
+        "tags": ["store"],
+        "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Inventory"}}}
+      }
+    }
+  },
+  "definitions": {
+    "Inventory": {
+      "type": "object",
+      "additionalProperties": {"type": "integer", "format": "int32"}
+    }
+  }
+}`
+
+	api, err := swagger.Load([]byte(doc))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"application/xml"}, api.Consumes)
+	assert.Equal(t, []string{"application/xml"}, api.Produces)
+	assert.Equal(t, "http://swagger.io", api.ExternalDocs.URL)
+	assert.Equal(t, &swagger.Property{Type: "integer", Format: "int32"}, api.Definitions["Inventory"].AdditionalProperties)
+
+	actual, err := api.RenderJSON()
+	assert.Nil(t, err)
+	assert.JSONEq(t, doc, string(actual))
+
+	content := api.ToOpenAPI(swagger.OpenAPI3).Paths["/inventory"].Get.Responses["200"].Content
+	assert.Contains(t, content, "application/xml", "expected endpoints to produce what the api produces")
+}
+
+func TestLoadInlineSchemas(t *testing.T) {
+	doc := `{
+  "swagger": "2.0",
+  "info": {
+    "title": "Store",
+    "version": "1.0.0",
+    "contact": {"name": "API Support", "url": "http://www.example.com/support", "email": "support@example.com"}
+  },
+  "paths": {
+    "/orders": {
+      "post": {
+        "tags": ["store"],
+        "parameters": [{
+          "in": "body",
+          "name": "order",
+          "required": true,
+          "schema": {
+            "type": "object",
+            "required": ["item"],
+            "properties": {
+              "item": {"type": "string", "title": "Item"},
+              "quantity": {"type": "integer", "format": "int32", "readOnly": true}
+            }
+          }
+        }],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "schema": {"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+	api, err := swagger.Load([]byte(doc))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, &swagger.Contact{Name: "API Support", URL: "http://www.example.com/support", Email: "support@example.com"}, api.Info.Contact)
+
+	schema := api.Paths["/orders"].Post.Parameters[0].Schema
+	assert.Equal(t, []string{"item"}, schema.Required)
+	assert.Equal(t, "Item", schema.Properties["item"].Title)
+	assert.True(t, schema.Properties["quantity"].ReadOnly)
+
+	actual, err := json.Marshal(api)
+	assert.Nil(t, err)
+	assert.JSONEq(t, doc, string(actual))
+}
+
+func TestLoadUnsupported(t *testing.T) {
+	docs := []string{
+		`{"swagger": "2.0", "parameters": {"limit": {"name": "limit", "in": "query", "type": "integer"}}}`,
+		`{"swagger": "2.0", "responses": {"NotFound": {"description": "not found"}}}`,
+		`{"swagger": "2.0", "paths": {"/pets": {"get": {"parameters": [{"$ref": "#/parameters/limit"}]}}}}`,
+		`{"swagger": "2.0", "paths": {"/pets": {"get": {"responses": {"404": {"$ref": "#/responses/NotFound"}}}}}}`,
+		`{"swagger": "2.0", "definitions": {"Pet": {"type": "object", "properties": {"name": {"type": "string", "xml": {"name": "n"}}}}}}`,
+	}
+	for _, doc := range docs {
+		_, err := swagger.Load([]byte(doc))
+		assert.NotNil(t, err, "expected an error loading %v", doc)
+	}
+}
+
+func TestLoadUnsupportedField(t *testing.T) {
+	doc := `{"swagger": "2.0", "info": {"title": "Store", "x-logo": {"url": "logo.png"}}}`
+	_, err := swagger.Load([]byte(doc))
+	assert.EqualError(t, err, "unsupported field info.x-logo")
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := swagger.Load([]byte(`{"paths": []}`))
+	assert.NotNil(t, err)
+
+	_, err = swagger.Load([]byte("swagger: [\n"))
+	assert.NotNil(t, err)
+
+	_, err = swagger.LoadFile("testdata/missing.json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,599 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Version identifies the specification format used to render the API
+type Version string
+
+const (
+	// Swagger2 renders the API as a Swagger 2.0 document
+	Swagger2 Version = "2.0"
+
+	// OpenAPI3 renders the API as an OpenAPI 3.0.x document
+	OpenAPI3 Version = "3.0.3"
+
+	// OpenAPI31 renders the API as an OpenAPI 3.1 document, whose schemas follow JSON Schema: exclusive bounds are
+	// numbers and nullable types include null
+	OpenAPI31 Version = "3.1.0"
+)
+
+// Server represents a server entity from the openapi definition
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// MediaType represents the schema of a single content type from the openapi definition
+type MediaType struct {
+	Schema  interface{} `json:"schema,omitempty"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+// RequestBody represents a request body from the openapi definition
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// OpenAPIParameter represents a non-body parameter from the openapi definition
+type OpenAPIParameter struct {
+	Name            string      `json:"name"`
+	In              string      `json:"in"`
+	Description     string      `json:"description,omitempty"`
+	Required        bool        `json:"required,omitempty"`
+	AllowEmptyValue bool        `json:"allowEmptyValue,omitempty"`
+	Style           string      `json:"style,omitempty"`
+	Explode         *bool       `json:"explode,omitempty"`
+	Schema          interface{} `json:"schema,omitempty"`
+}
+
+// OpenAPIHeader represents a response header from the openapi definition
+type OpenAPIHeader struct {
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+}
+
+// OpenAPIResponse represents a response from the openapi definition
+type OpenAPIResponse struct {
+	Description string                   `json:"description"`
+	Headers     map[string]OpenAPIHeader `json:"headers,omitempty"`
+	Content     map[string]MediaType     `json:"content,omitempty"`
+}
+
+// Operation represents a single operation on a path from the openapi definition
+type Operation struct {
+	Tags        []string                   `json:"tags,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	OperationID string                     `json:"operationId,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody               `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Security    *SecurityRequirement       `json:"security,omitempty"`
+}
+
+// PathItem represents all the operations associated with a particular path from the openapi definition
+type PathItem struct {
+	Delete  *Operation `json:"delete,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Get     *Operation `json:"get,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Trace   *Operation `json:"trace,omitempty"`
+}
+
+// Components holds the reusable schemas and security schemes of the openapi definition
+type Components struct {
+	Schemas         map[string]interface{} `json:"schemas,omitempty"`
+	SecuritySchemes map[string]interface{} `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPI provides the top level encapsulation for the openapi 3 definition
+type OpenAPI struct {
+	OpenAPI      string               `json:"openapi"`
+	Info         Info                 `json:"info"`
+	Servers      []Server             `json:"servers,omitempty"`
+	Paths        map[string]*PathItem `json:"paths"`
+	Components   *Components          `json:"components,omitempty"`
+	Tags         []Tag                `json:"tags,omitempty"`
+	Security     *SecurityRequirement `json:"security,omitempty"`
+	ExternalDocs *Docs                `json:"externalDocs,omitempty"`
+}
+
+// ToOpenAPI converts the swagger definition into an openapi 3 definition of the specified version
+func (a *API) ToOpenAPI(version Version) *OpenAPI {
+	doc := &OpenAPI{
+		OpenAPI:      string(version),
+		Info:         a.Info,
+		Servers:      a.servers(),
+		Paths:        map[string]*PathItem{},
+		Tags:         a.Tags,
+		Security:     a.Security,
+		ExternalDocs: a.ExternalDocs,
+	}
+
+	for p, endpoints := range a.Paths {
+		item := &PathItem{}
+		endpoints.Walk(func(e *Endpoint) {
+			op := a.toOperation(e)
+			switch strings.ToUpper(e.Method) {
+			case "DELETE":
+				item.Delete = op
+			case "HEAD":
+				item.Head = op
+			case "GET":
+				item.Get = op
+			case "OPTIONS":
+				item.Options = op
+			case "POST":
+				item.Post = op
+			case "PUT":
+				item.Put = op
+			case "PATCH":
+				item.Patch = op
+			case "TRACE":
+				item.Trace = op
+			}
+		})
+		doc.Paths[p] = item
+	}
+
+	if len(a.Definitions) > 0 || len(a.SecurityDefinitions) > 0 {
+		doc.Components = &Components{}
+	}
+	if len(a.Definitions) > 0 {
+		doc.Components.Schemas = map[string]interface{}{}
+		for name, def := range a.Definitions {
+			doc.Components.Schemas[name] = openAPISchema(def)
+		}
+	}
+	if len(a.SecurityDefinitions) > 0 {
+		doc.Components.SecuritySchemes = map[string]interface{}{}
+		for name, scheme := range a.SecurityDefinitions {
+			doc.Components.SecuritySchemes[name] = openAPISecurityScheme(scheme)
+		}
+	}
+
+	if version == OpenAPI31 {
+		doc.walkSchemas(jsonSchema)
+	}
+
+	return doc
+}
+
+// Render returns the static schema as json, in the format of the requested version
+func (a *API) Render(version Version) ([]byte, error) {
+	switch version {
+	case Swagger2:
+		return a.RenderJSON()
+	case OpenAPI3, OpenAPI31:
+		return json.MarshalIndent(a.ToOpenAPI(version), "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported version, %v", version)
+	}
+}
+
+// servers builds the openapi servers from host, basePath and schemes
+func (a *API) servers() []Server {
+	basePath := strings.TrimSuffix(a.BasePath, "/")
+	if a.Host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []Server{{URL: basePath}}
+	}
+
+	// without schemes, the server is relative to the scheme used to fetch the definition
+	if len(a.Schemes) == 0 {
+		return []Server{{URL: "//" + a.Host + basePath}}
+	}
+
+	servers := make([]Server, 0, len(a.Schemes))
+	for _, scheme := range a.Schemes {
+		servers = append(servers, Server{URL: scheme + "://" + a.Host + basePath})
+	}
+	return servers
+}
+
+// toOperation converts the endpoint; endpoints without their own consumes and produces use those of the api
+func (a *API) toOperation(e *Endpoint) *Operation {
+	op := &Operation{
+		Summary:     e.Summary,
+		Description: e.Description,
+		OperationID: e.OperationID,
+		Deprecated:  e.Deprecated,
+		Security:    e.Security,
+	}
+	if len(e.Tags) > 0 {
+		op.Tags = e.Tags
+	}
+
+	consumes := e.Consumes
+	if len(consumes) == 0 {
+		consumes = a.Consumes
+	}
+	if len(consumes) == 0 {
+		consumes = []string{"application/json"}
+	}
+
+	var form []Parameter
+	for _, p := range e.Parameters {
+		switch p.In {
+		case "body":
+			op.RequestBody = &RequestBody{
+				Description: p.Description,
+				Required:    p.Required,
+				Content:     content(consumes, openAPISchema(p.Schema)),
+			}
+		case "formData":
+			form = append(form, p)
+		default:
+			param := OpenAPIParameter{
+				Name:            p.Name,
+				In:              p.In,
+				Description:     p.Description,
+				Required:        p.Required || p.In == "path",
+				AllowEmptyValue: p.AllowEmptyValue,
+				Schema:          parameterSchema(p),
+			}
+			if p.Type == "array" {
+				param.Style, param.Explode = style(p)
+			}
+			op.Parameters = append(op.Parameters, param)
+		}
+	}
+	if form != nil {
+		op.RequestBody = formRequestBody(consumes, form)
+	}
+
+	if e.Responses != nil {
+		produces := e.Produces
+		if len(produces) == 0 {
+			produces = a.Produces
+		}
+		if len(produces) == 0 {
+			produces = []string{"application/json"}
+		}
+
+		op.Responses = map[string]OpenAPIResponse{}
+		for code, r := range e.Responses {
+			response := OpenAPIResponse{
+				Description: r.Description,
+			}
+			var schema interface{}
+			if r.Schema != nil {
+				schema = openAPISchema(r.Schema)
+				response.Content = content(produces, schema)
+			}
+			for _, mediaType := range sortedKeys(r.Examples) {
+				if response.Content == nil {
+					response.Content = map[string]MediaType{}
+				}
+				response.Content[mediaType] = MediaType{Schema: schema, Example: r.Examples[mediaType]}
+			}
+			if r.Headers != nil {
+				response.Headers = map[string]OpenAPIHeader{}
+				for name, h := range r.Headers {
+					response.Headers[name] = OpenAPIHeader{
+						Description: h.Description,
+						Schema:      openAPISchema(Items{Type: h.Type, Format: h.Format}),
+					}
+				}
+			}
+			op.Responses[code] = response
+		}
+	}
+
+	return op
+}
+
+// formRequestBody folds formData parameters into a single object schema
+func formRequestBody(consumes []string, params []Parameter) *RequestBody {
+	properties := map[string]interface{}{}
+	required := []string{}
+	hasFile := false
+	for _, p := range params {
+		if p.Type == "file" {
+			hasFile = true
+		}
+		if p.Required {
+			required = append(required, p.Name)
+		}
+		schema := parameterSchema(p)
+		if p.Description != "" {
+			if m, ok := schema.(map[string]interface{}); ok {
+				m["description"] = p.Description
+			}
+		}
+		properties[p.Name] = schema
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var mediaTypes []string
+	for _, c := range consumes {
+		if strings.HasPrefix(c, "multipart/") || c == "application/x-www-form-urlencoded" {
+			mediaTypes = append(mediaTypes, c)
+		}
+	}
+	if mediaTypes == nil {
+		if hasFile {
+			mediaTypes = []string{"multipart/form-data"}
+		} else {
+			mediaTypes = []string{"application/x-www-form-urlencoded"}
+		}
+	}
+
+	return &RequestBody{
+		Required: len(required) > 0,
+		Content:  content(mediaTypes, schema),
+	}
+}
+
+func content(mediaTypes []string, schema interface{}) map[string]MediaType {
+	c := map[string]MediaType{}
+	for _, m := range mediaTypes {
+		c[m] = MediaType{Schema: schema}
+	}
+	return c
+}
+
+// parameterSchema extracts the schema keywords of a swagger 2.0 parameter
+func parameterSchema(p Parameter) interface{} {
+	schema, ok := openAPISchema(p).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, k := range []string{"in", "name", "description", "required", "schema", "allowEmptyValue", "collectionFormat"} {
+		delete(schema, k)
+	}
+	return schema
+}
+
+// style converts the collectionFormat of an array parameter into the openapi 3 style and explode; tsv has no
+// equivalent and is left to the defaults
+func style(p Parameter) (string, *bool) {
+	explode := p.CollectionFormat == "multi"
+	switch p.CollectionFormat {
+	case "", "csv", "multi":
+		if p.In == "query" {
+			return "form", &explode
+		}
+		return "simple", nil
+	case "ssv":
+		return "spaceDelimited", &explode
+	case "pipes":
+		return "pipeDelimited", &explode
+	default:
+		return "", nil
+	}
+}
+
+// openAPISchema converts a swagger 2.0 schema fragment into its generic openapi 3 equivalent
+func openAPISchema(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var schema interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil
+	}
+
+	return convertSchema(schema)
+}
+
+func convertSchema(v interface{}) interface{} {
+	schema, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	for k, item := range schema {
+		switch k {
+		case "$ref":
+			if ref, ok := item.(string); ok {
+				schema[k] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+			}
+		case "x-nullable":
+			delete(schema, k)
+			schema["nullable"] = item
+		case "x-example":
+			// openapi 3 schemas have examples of their own
+			delete(schema, k)
+			schema["example"] = item
+		case "discriminator":
+			// openapi 3 names the discriminator property in an object; values default to the schema names
+			if name, ok := item.(string); ok {
+				schema[k] = map[string]interface{}{"propertyName": name}
+			}
+		case "items", "additionalProperties", "not":
+			schema[k] = convertSchema(item)
+		case "allOf", "anyOf", "oneOf":
+			if list, ok := item.([]interface{}); ok {
+				for i := range list {
+					list[i] = convertSchema(list[i])
+				}
+			}
+		case "properties":
+			if properties, ok := item.(map[string]interface{}); ok {
+				for name, p := range properties {
+					properties[name] = convertSchema(p)
+				}
+			}
+		}
+	}
+
+	// openapi 3 represents files as binary strings
+	if schema["type"] == "file" {
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	}
+
+	return schema
+}
+
+// walkSchemas replaces every top level schema of the document with the result of fn
+func (doc *OpenAPI) walkSchemas(fn func(interface{}) interface{}) {
+	walkContent := func(c map[string]MediaType) {
+		for mediaType, m := range c {
+			m.Schema = fn(m.Schema)
+			c[mediaType] = m
+		}
+	}
+
+	for _, item := range doc.Paths {
+		for _, op := range []*Operation{item.Delete, item.Head, item.Get, item.Options, item.Post, item.Put, item.Patch, item.Trace} {
+			if op == nil {
+				continue
+			}
+			for i := range op.Parameters {
+				op.Parameters[i].Schema = fn(op.Parameters[i].Schema)
+			}
+			if op.RequestBody != nil {
+				walkContent(op.RequestBody.Content)
+			}
+			for _, r := range op.Responses {
+				walkContent(r.Content)
+				for name, h := range r.Headers {
+					h.Schema = fn(h.Schema)
+					r.Headers[name] = h
+				}
+			}
+		}
+	}
+	if doc.Components != nil {
+		for name, schema := range doc.Components.Schemas {
+			doc.Components.Schemas[name] = fn(schema)
+		}
+	}
+}
+
+// jsonSchema converts an openapi 3.0 schema into its openapi 3.1 form; schemas shared by several media types are
+// visited more than once, so the conversion leaves converted schemas unchanged
+func jsonSchema(v interface{}) interface{} {
+	schema, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	for _, bound := range []string{"Minimum", "Maximum"} {
+		exclusive, limit := "exclusive"+bound, strings.ToLower(bound)
+		if b, ok := schema[exclusive].(bool); ok {
+			delete(schema, exclusive)
+			if value, ok := schema[limit]; ok && b {
+				delete(schema, limit)
+				schema[exclusive] = value
+			}
+		}
+	}
+
+	if nullable, ok := schema["nullable"].(bool); ok {
+		delete(schema, "nullable")
+		if nullable {
+			if typ, ok := schema["type"].(string); ok {
+				schema["type"] = []interface{}{typ, "null"}
+				if enum, ok := schema["enum"].([]interface{}); ok {
+					schema["enum"] = append(enum, nil)
+				}
+			} else if ref, ok := schema["$ref"]; ok {
+				delete(schema, "$ref")
+				schema["anyOf"] = []interface{}{
+					map[string]interface{}{"$ref": ref},
+					map[string]interface{}{"type": "null"},
+				}
+			}
+		}
+	}
+
+	for k, item := range schema {
+		switch k {
+		case "items", "additionalProperties", "not":
+			schema[k] = jsonSchema(item)
+		case "allOf", "anyOf", "oneOf":
+			if list, ok := item.([]interface{}); ok {
+				for i := range list {
+					list[i] = jsonSchema(list[i])
+				}
+			}
+		case "properties":
+			if properties, ok := item.(map[string]interface{}); ok {
+				for name, p := range properties {
+					properties[name] = jsonSchema(p)
+				}
+			}
+		}
+	}
+
+	return schema
+}
+
+// openAPISecurityScheme converts a swagger 2.0 security definition into an openapi 3 security scheme
+func openAPISecurityScheme(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	scheme := map[string]interface{}{}
+	if err := json.Unmarshal(data, &scheme); err != nil {
+		return v
+	}
+
+	switch scheme["type"] {
+	case "basic":
+		scheme["type"] = "http"
+		scheme["scheme"] = "basic"
+
+	case "oauth2":
+		flow := map[string]interface{}{
+			"scopes": map[string]interface{}{},
+		}
+		for _, k := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+			if item, ok := scheme[k]; ok {
+				flow[k] = item
+				delete(scheme, k)
+			}
+		}
+
+		name, _ := scheme["flow"].(string)
+		delete(scheme, "flow")
+		switch name {
+		case "application":
+			name = "clientCredentials"
+		case "accessCode":
+			name = "authorizationCode"
+		}
+		scheme["flows"] = map[string]interface{}{name: flow}
+	}
+
+	return scheme
+}
@@ -0,0 +1,285 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Category struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Animal struct {
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	Category *Category `json:"category"`
+}
+
+func openAPIFixture(t *testing.T) *swagger.API {
+	usePackageName(t, false)
+
+	post := endpoint.New("post", "/pet", "Add a new pet to the store",
+		endpoint.Body(Animal{}, "Pet object that needs to be added to the store", true),
+		endpoint.Response(http.StatusOK, Animal{}, "Successfully added pet"),
+	)
+	get := endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+		endpoint.Path("petId", "integer", "int64", "ID of pet to return"),
+		endpoint.Query("verbose", "boolean", "", "include everything", false),
+		endpoint.Response(http.StatusOK, Animal{}, "successful operation",
+			endpoint.Header("X-Rate-Limit", "integer", "int32", "calls per hour allowed by the user"),
+		),
+	)
+	upload := endpoint.New("post", "/pet/{petId}/image", "Upload an image",
+		endpoint.Path("petId", "integer", "int64", "ID of pet to update"),
+		endpoint.FormData("file", "file", "", "file to upload", true),
+		endpoint.Response(http.StatusNoContent, "", "uploaded"),
+	)
+
+	return swag.New(
+		swag.Host("petstore.swagger.io"),
+		swag.BasePath("/v2"),
+		swag.Schemes("https", "http"),
+		swag.Endpoints(post, get, upload),
+		swag.SecurityScheme("basic", swagger.BasicSecurity()),
+		swag.SecurityScheme("petstore_auth",
+			swagger.OAuth2Security("accessCode", "http://example.com/oauth/authorize", "http://example.com/oauth/token"),
+			swagger.OAuth2Scope("read:pets", "read your pets"),
+		),
+	)
+}
+
+func TestToOpenAPI(t *testing.T) {
+	doc := openAPIFixture(t).ToOpenAPI(swagger.OpenAPI3)
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Equal(t, []swagger.Server{
+		{URL: "https://petstore.swagger.io/v2"},
+		{URL: "http://petstore.swagger.io/v2"},
+	}, doc.Servers)
+
+	post := doc.Paths["/pet"].Post
+	if assert.NotNil(t, post) && assert.NotNil(t, post.RequestBody) {
+		assert.True(t, post.RequestBody.Required)
+		assert.Equal(t,
+			map[string]interface{}{"$ref": "#/components/schemas/Animal"},
+			post.RequestBody.Content["application/json"].Schema,
+		)
+	}
+	assert.Empty(t, post.Parameters)
+
+	get := doc.Paths["/pet/{petId}"].Get
+	if assert.NotNil(t, get) && assert.Len(t, get.Parameters, 2) {
+		assert.Equal(t, "path", get.Parameters[0].In)
+		assert.True(t, get.Parameters[0].Required)
+		assert.Equal(t, map[string]interface{}{"type": "integer", "format": "int64"}, get.Parameters[0].Schema)
+		assert.Equal(t, map[string]interface{}{"type": "boolean"}, get.Parameters[1].Schema)
+	}
+	ok := get.Responses["200"]
+	assert.Equal(t,
+		map[string]interface{}{"$ref": "#/components/schemas/Animal"},
+		ok.Content["application/json"].Schema,
+	)
+	assert.Equal(t, "calls per hour allowed by the user", ok.Headers["X-Rate-Limit"].Description)
+
+	upload := doc.Paths["/pet/{petId}/image"].Post
+	if assert.NotNil(t, upload.RequestBody) {
+		schema := upload.RequestBody.Content["multipart/form-data"].Schema.(map[string]interface{})
+		assert.Equal(t, []string{"file"}, schema["required"])
+		assert.Equal(t, map[string]interface{}{
+			"type":        "string",
+			"format":      "binary",
+			"description": "file to upload",
+		}, schema["properties"].(map[string]interface{})["file"])
+	}
+	assert.Nil(t, upload.Responses["204"].Content)
+
+	animal := doc.Components.Schemas["Animal"].(map[string]interface{})
+	category := animal["properties"].(map[string]interface{})["category"]
+	assert.Equal(t, map[string]interface{}{
+		"$ref":     "#/components/schemas/Category",
+		"nullable": true,
+	}, category)
+
+	assert.Equal(t, map[string]interface{}{"type": "http", "scheme": "basic"}, doc.Components.SecuritySchemes["basic"])
+	oauth := doc.Components.SecuritySchemes["petstore_auth"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"authorizationCode": map[string]interface{}{
+			"authorizationUrl": "http://example.com/oauth/authorize",
+			"tokenUrl":         "http://example.com/oauth/token",
+			"scopes":           map[string]interface{}{"read:pets": "read your pets"},
+		},
+	}, oauth["flows"])
+}
+
+func TestOpenAPIExamples(t *testing.T) {
+	get := endpoint.New("get", "/pet", "List pets",
+		endpoint.QueryList([]swagger.Parameter{
+			{Name: "limit", Type: "integer", Description: "page size", Example: 10},
+		}),
+		endpoint.Response(http.StatusOK, []Animal{}, "successful operation",
+			endpoint.Example("application/json", []interface{}{map[string]interface{}{"id": 1, "name": "rex"}}),
+		),
+	)
+	api := swag.New(swag.Endpoints(get))
+
+	data, err := json.Marshal(api)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"x-example":10`)
+	assert.Contains(t, string(data), `"examples":{"application/json":[{"id":1,"name":"rex"}]}`)
+
+	op := api.ToOpenAPI(swagger.OpenAPI3).Paths["/pet"].Get
+	if assert.Len(t, op.Parameters, 1) {
+		assert.Equal(t, map[string]interface{}{"type": "integer", "example": float64(10)}, op.Parameters[0].Schema)
+	}
+	assert.Equal(t,
+		[]interface{}{map[string]interface{}{"id": 1, "name": "rex"}},
+		op.Responses["200"].Content["application/json"].Example,
+	)
+}
+
+type Discount struct {
+	Percent  float64   `json:"percent" minimum:"0" maximum:"100" exclusive_maximum:"true"`
+	Price    float32   `json:"price" minimum:"0.01" multiple_of:"0.01"`
+	Category *Category `json:"category"`
+}
+
+func TestOpenAPI31(t *testing.T) {
+	usePackageName(t, false)
+
+	post := endpoint.New("post", "/discount", "Add a discount",
+		endpoint.Body(Discount{}, "the discount", true),
+		endpoint.Query("limit", "integer", "", "page size", false),
+	)
+	api := swag.New(swag.Endpoints(post))
+
+	doc := api.ToOpenAPI(swagger.OpenAPI3)
+	percent := doc.Components.Schemas["Discount"].(map[string]interface{})["properties"].(map[string]interface{})["percent"]
+	assert.Equal(t, map[string]interface{}{
+		"type":             "number",
+		"format":           "double",
+		"minimum":          float64(0),
+		"maximum":          float64(100),
+		"exclusiveMaximum": true,
+	}, percent)
+
+	doc = api.ToOpenAPI(swagger.OpenAPI31)
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	properties := doc.Components.Schemas["Discount"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"type":             "number",
+		"format":           "double",
+		"minimum":          float64(0),
+		"exclusiveMaximum": float64(100),
+	}, properties["percent"])
+	assert.Equal(t, map[string]interface{}{
+		"type":       "number",
+		"format":     "float",
+		"minimum":    0.01,
+		"multipleOf": 0.01,
+	}, properties["price"])
+	assert.Equal(t, map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Category"},
+			map[string]interface{}{"type": "null"},
+		},
+	}, properties["category"])
+
+	data, err := api.Render(swagger.OpenAPI31)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"openapi": "3.1.0"`)
+}
+
+func TestOpenAPICollectionFormat(t *testing.T) {
+	get := endpoint.New("get", "/pet", "List pets",
+		endpoint.Query("status", "array", "", "statuses", false,
+			endpoint.Items("string", ""),
+			endpoint.CollectionFormat("multi"),
+			endpoint.AllowEmptyValue(),
+		),
+		endpoint.Query("tags", "array", "", "tags", false, endpoint.Items("string", ""), endpoint.CollectionFormat("pipes")),
+		endpoint.RequestHeader("X-Tags", "array", "", "tags", false, endpoint.Items("string", "")),
+	)
+	op := swag.New(swag.Endpoints(get)).ToOpenAPI(swagger.OpenAPI3).Paths["/pet"].Get
+
+	explode, noExplode := true, false
+	if assert.Len(t, op.Parameters, 3) {
+		assert.Equal(t, swagger.OpenAPIParameter{
+			Name:            "status",
+			In:              "query",
+			Description:     "statuses",
+			AllowEmptyValue: true,
+			Style:           "form",
+			Explode:         &explode,
+			Schema:          map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		}, op.Parameters[0])
+		assert.Equal(t, "pipeDelimited", op.Parameters[1].Style)
+		assert.Equal(t, &noExplode, op.Parameters[1].Explode)
+		assert.Equal(t, "simple", op.Parameters[2].Style)
+		assert.Nil(t, op.Parameters[2].Explode)
+	}
+}
+
+func TestOpenAPIServers(t *testing.T) {
+	api := swag.New()
+	assert.Nil(t, api.ToOpenAPI(swagger.OpenAPI3).Servers)
+
+	api = swag.New(swag.Host("example.com"))
+	assert.Equal(t, []swagger.Server{{URL: "//example.com"}}, api.ToOpenAPI(swagger.OpenAPI3).Servers)
+
+	api = swag.New(swag.BasePath("/api"))
+	assert.Equal(t, []swagger.Server{{URL: "/api"}}, api.ToOpenAPI(swagger.OpenAPI3).Servers)
+}
+
+func TestRender(t *testing.T) {
+	api := openAPIFixture(t)
+
+	data, err := api.Render(swagger.Swagger2)
+	assert.Nil(t, err)
+	expected, _ := api.RenderJSON()
+	assert.Equal(t, expected, data)
+
+	data, err = api.Render(swagger.OpenAPI3)
+	assert.Nil(t, err)
+	doc := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.NotContains(t, doc, "swagger")
+	assert.NotContains(t, doc, "definitions")
+
+	_, err = api.Render(swagger.Version("1.2"))
+	assert.NotNil(t, err)
+}
+
+func TestVersionHandler(t *testing.T) {
+	api := openAPIFixture(t)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/swagger", nil)
+	w := httptest.NewRecorder()
+	api.VersionHandler(swagger.OpenAPI3, false)(w, req)
+
+	doc := map[string]interface{}{}
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON serializes an Object, including its extensions, or only its $ref when set; its properties are written
+// in the order recorded in PropertyOrder, if any, otherwise sorted by name
+func (o Object) MarshalJSON() ([]byte, error) {
+	type object Object
+	if o.Ref != "" {
+		return json.Marshal(struct {
+			Ref string `json:"$ref"`
+		}{Ref: o.Ref})
+	}
+
+	if o.AdditionalProperties == nil {
+		o.AdditionalProperties = false
+	}
+
+	var data []byte
+	var err error
+	if len(o.PropertyOrder) == 0 || len(o.Properties) == 0 {
+		data, err = json.Marshal(object(o))
+	} else {
+		data, err = json.Marshal(struct {
+			object
+			Properties orderedProperties `json:"properties,omitempty"`
+		}{
+			object:     object(o),
+			Properties: orderedProperties{order: o.PropertyOrder, properties: o.Properties},
+		})
+	}
+	if err != nil || len(o.Extensions) == 0 {
+		return data, err
+	}
+
+	return appendExtensions(data, o.Extensions)
+}
+
+// appendExtensions adds the extensions, sorted by name, to the json object in data
+func appendExtensions(data []byte, extensions map[string]interface{}) ([]byte, error) {
+	buf := bytes.NewBuffer(bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}")))
+	for i, name := range sortedKeys(extensions) {
+		if i > 0 || buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(extensions[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// orderedProperties writes properties in the specified order, followed by any properties missing from the order
+// sorted by name
+type orderedProperties struct {
+	order      []string
+	properties map[string]Property
+}
+
+func (o orderedProperties) MarshalJSON() ([]byte, error) {
+	names := make([]string, 0, len(o.properties))
+	seen := map[string]bool{}
+	for _, name := range o.order {
+		if _, ok := o.properties[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range sortedKeys(o.properties) {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.properties[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
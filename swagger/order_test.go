@@ -0,0 +1,79 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Audit struct {
+	Updated string `json:"updated"`
+	Created string `json:"created"`
+}
+
+type Ordered struct {
+	Zebra string `json:"zebra"`
+	Audit
+	Apple int  `json:"apple"`
+	Mango bool `json:"mango"`
+}
+
+// propertyOrder returns the names of the properties of the Ordered definition in the order they are rendered
+func propertyOrder(t *testing.T, options ...swag.Option) []string {
+	options = append(options, swag.Endpoints(endpoint.New("get", "/ordered", "ordered",
+		endpoint.Response(200, Ordered{}, "ok"),
+	)))
+	api := swag.New(options...)
+
+	data, err := json.Marshal(api.Definitions["Ordered"])
+	assert.Nil(t, err)
+
+	names := []string{"apple", "created", "mango", "updated", "zebra"}
+	sort.Slice(names, func(i, j int) bool {
+		return bytes.Index(data, []byte(`"`+names[i]+`":`)) < bytes.Index(data, []byte(`"`+names[j]+`":`))
+	})
+	return names
+}
+
+func TestStructFieldOrder(t *testing.T) {
+	usePackageName(t, false)
+	assert.Equal(t, []string{"apple", "created", "mango", "updated", "zebra"}, propertyOrder(t))
+
+	swagger.StructFieldOrder = true
+	defer func() { swagger.StructFieldOrder = false }()
+	assert.Equal(t, []string{"zebra", "updated", "created", "apple", "mango"}, propertyOrder(t))
+}
+
+func TestRegistryStructFieldOrder(t *testing.T) {
+	usePackageName(t, false)
+
+	r := swagger.NewRegistry()
+	r.UsePackageName = false
+	r.StructFieldOrder = true
+	assert.Equal(t, []string{"zebra", "updated", "created", "apple", "mango"}, propertyOrder(t, swag.Registry(r)))
+
+	// the order is recorded on the generated definitions, the package level setting does not affect other registries
+	other := swagger.NewRegistry()
+	other.UsePackageName = false
+	assert.Equal(t, []string{"apple", "created", "mango", "updated", "zebra"}, propertyOrder(t))
+	assert.Equal(t, []string{"apple", "created", "mango", "updated", "zebra"}, propertyOrder(t, swag.Registry(other)))
+}
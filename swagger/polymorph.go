@@ -0,0 +1,214 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EmbedAsAllOf can be set to true to reference the definitions of embedded structs with allOf rather than copying
+// their fields into the embedding definition
+var EmbedAsAllOf = false
+
+// polymorphism describes an interface type whose values are one of several registered struct types
+type polymorphism struct {
+	discriminator string
+	types         []reflect.Type
+}
+
+var (
+	// subTypes maps each registered interface type to its implementations
+	subTypes = map[reflect.Type]polymorphism{}
+
+	// baseTypes maps each registered implementation to its interface type
+	baseTypes = map[reflect.Type]reflect.Type{}
+)
+
+// RegisterSubTypes registers the struct types implementing an interface, so that properties of the interface type
+// refer to a definition with a discriminator and each implementation is defined as allOf that definition and its own
+// fields. iface must be a nil pointer to the interface, e.g. (*Animal)(nil); the discriminator property holds the
+// definition name of the implementation
+func RegisterSubTypes(iface interface{}, discriminator string, implementations ...interface{}) {
+	defaultRegistry().RegisterSubTypes(iface, discriminator, implementations...)
+}
+
+// RegisterSubTypes registers the struct types implementing an interface with the registry; see the package level
+// RegisterSubTypes
+func (r *Registry) RegisterSubTypes(iface interface{}, discriminator string, implementations ...interface{}) {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		panic(&SchemaError{Type: t, Err: fmt.Errorf("RegisterSubTypes requires a nil pointer to an interface, got %T", iface)})
+	}
+	t = t.Elem()
+
+	poly := polymorphism{discriminator: discriminator}
+	for _, impl := range implementations {
+		it := reflect.TypeOf(impl)
+		if it == nil || !it.Implements(t) {
+			panic(&SchemaError{Type: it, Err: fmt.Errorf("RegisterSubTypes: %T does not implement %v", impl, t)})
+		}
+		for it.Kind() == reflect.Ptr {
+			it = it.Elem()
+		}
+		if it.Kind() != reflect.Struct {
+			panic(&SchemaError{Type: it, Err: fmt.Errorf("RegisterSubTypes: implementation of %v is not a struct", t)})
+		}
+		poly.types = append(poly.types, it)
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for _, it := range poly.types {
+		r.baseTypes[it] = t
+	}
+	r.subTypes[t] = poly
+	r.reset()
+}
+
+// defineBase creates the definition of a registered interface type; the discriminator is required and limited to
+// the definition names of the implementations
+func (r *Registry) defineBase(t reflect.Type) Object {
+	poly := r.subTypes[t]
+
+	names := make([]interface{}, 0, len(poly.types))
+	for _, st := range poly.types {
+		names = append(names, r.makeName(st))
+	}
+
+	return Object{
+		GoType:        t,
+		Name:          r.makeName(t),
+		Type:          "object",
+		Required:      []string{poly.discriminator},
+		Discriminator: poly.discriminator,
+		Properties: map[string]Property{
+			poly.discriminator: {Type: "string", Enum: names},
+		},
+		AdditionalProperties: true,
+	}
+}
+
+// extend turns the object into allOf the parents followed by the object's own properties; title, description and
+// extensions stay on the returned object
+func (o Object) extend(parents []Object) Object {
+	own := Object{
+		Type:                 "object",
+		Required:             o.Required,
+		Properties:           o.Properties,
+		PropertyOrder:        o.PropertyOrder,
+		AdditionalProperties: true,
+	}
+
+	return Object{
+		IsArray:     o.IsArray,
+		GoType:      o.GoType,
+		Name:        o.Name,
+		Type:        "object",
+		Title:       o.Title,
+		Description: o.Description,
+		Example:     o.Example,
+		AllOf:       append(parents, own),
+		Extensions:  o.Extensions,
+	}
+}
+
+// removeProperty removes the named property and any requirement for it
+func (o *Object) removeProperty(name string) {
+	if _, ok := o.Properties[name]; !ok {
+		return
+	}
+
+	delete(o.Properties, name)
+	var required []string
+	for _, r := range o.Required {
+		if r != name {
+			required = append(required, r)
+		}
+	}
+	o.Required = required
+}
+
+// allProperties returns the properties of the object and of its inline allOf parts
+func (o Object) allProperties() []Property {
+	properties := make([]Property, 0, len(o.Properties))
+	for _, p := range o.Properties {
+		properties = append(properties, p)
+	}
+	for _, part := range o.AllOf {
+		if part.Ref == "" {
+			properties = append(properties, part.allProperties()...)
+		}
+	}
+	return properties
+}
+
+// referencedType returns the go type of the definition the property, or its items or values, refers to; nil when it
+// refers to none or was not generated from a go type
+func referencedType(p Property) reflect.Type {
+	if p.Ref != "" {
+		return p.GoType
+	}
+	for items := p.Items; items != nil; items = items.Items {
+		if items.Ref != "" {
+			return p.GoType // the go type of an array property is that of its innermost items
+		}
+		if ap, ok := items.AdditionalProperties.(*Property); ok {
+			return referencedType(*ap)
+		}
+	}
+	if ap, ok := p.AdditionalProperties.(*Property); ok {
+		return referencedType(*ap)
+	}
+	return nil
+}
+
+// addDefinitions adds the definition of t to objMap, along with the implementations of t when it is a registered
+// interface; returns true if anything was added
+func (r *Registry) addDefinitions(objMap map[string]Object, t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if _, objects, ok := r.resolve(t); ok {
+		added := false
+		for _, obj := range objects {
+			if existing, exists := objMap[obj.Name]; exists {
+				r.checkCollision(existing, obj)
+				continue
+			}
+			objMap[obj.Name] = obj
+			added = true
+		}
+		return added
+	}
+	if existing, exists := objMap[r.makeName(t)]; exists {
+		r.checkCollision(existing, Object{Name: existing.Name, GoType: t})
+		return false
+	}
+
+	poly, ok := r.subTypes[t]
+	if !ok {
+		child := r.defineObject(t)
+		objMap[child.Name] = child
+		return true
+	}
+
+	base := r.defineBase(t)
+	objMap[base.Name] = base
+	for _, st := range poly.types {
+		r.addDefinitions(objMap, st)
+	}
+	return true
+}
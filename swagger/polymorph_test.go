@@ -0,0 +1,170 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius" required:"true"`
+}
+
+func (c Circle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+type Drawing struct {
+	Background Shape   `json:"background"`
+	Shapes     []Shape `json:"shapes"`
+}
+
+func TestSubTypes(t *testing.T) {
+	usePackageName(t, false)
+	swagger.RegisterSubTypes((*Shape)(nil), "kind", Circle{}, &Square{})
+
+	api := swag.New(swag.Endpoints(endpoint.New("get", "/drawing", "Get drawing",
+		endpoint.Response(200, Drawing{}, "ok"),
+	)))
+	assert.Empty(t, api.Validate())
+
+	data, err := json.Marshal(api.Definitions)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"Drawing": {
+			"type": "object",
+			"properties": {
+				"background": {"$ref": "#/definitions/Shape"},
+				"shapes": {"type": "array", "items": {"$ref": "#/definitions/Shape"}}
+			},
+			"additionalProperties": false
+		},
+		"Shape": {
+			"type": "object",
+			"discriminator": "kind",
+			"required": ["kind"],
+			"properties": {
+				"kind": {"type": "string", "enum": ["Circle", "Square"]}
+			},
+			"additionalProperties": true
+		},
+		"Circle": {
+			"type": "object",
+			"additionalProperties": false,
+			"allOf": [
+				{"$ref": "#/definitions/Shape"},
+				{
+					"type": "object",
+					"required": ["radius"],
+					"properties": {"radius": {"type": "number", "format": "double"}},
+					"additionalProperties": true
+				}
+			]
+		},
+		"Square": {
+			"type": "object",
+			"additionalProperties": false,
+			"allOf": [
+				{"$ref": "#/definitions/Shape"},
+				{
+					"type": "object",
+					"properties": {"side": {"type": "number", "format": "double"}},
+					"additionalProperties": true
+				}
+			]
+		}
+	}`, string(data))
+
+	data, err = api.Render(swagger.OpenAPI3)
+	assert.Nil(t, err)
+	var doc struct {
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.Nil(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, map[string]interface{}{"propertyName": "kind"}, doc.Components.Schemas["Shape"]["discriminator"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/Shape"}, doc.Components.Schemas["Square"]["allOf"].([]interface{})[0])
+}
+
+type Stamp struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type Document struct {
+	Stamp
+	Title string `json:"title"`
+}
+
+func TestEmbedAsAllOf(t *testing.T) {
+	usePackageName(t, false)
+	swagger.EmbedAsAllOf = true
+	defer func() { swagger.EmbedAsAllOf = false }()
+
+	api := swag.New(swag.Endpoints(endpoint.New("get", "/document", "Get document",
+		endpoint.Response(200, Document{}, "ok"),
+	)))
+
+	data, err := json.Marshal(api.Definitions)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"Stamp": {
+			"type": "object",
+			"properties": {"createdBy": {"type": "string"}},
+			"additionalProperties": false
+		},
+		"Document": {
+			"type": "object",
+			"additionalProperties": false,
+			"allOf": [
+				{"$ref": "#/definitions/Stamp"},
+				{
+					"type": "object",
+					"properties": {"title": {"type": "string"}},
+					"additionalProperties": true
+				}
+			]
+		}
+	}`, string(data))
+}
+
+func TestRegisterSubTypesPanics(t *testing.T) {
+	assert.PanicsWithError(t, "swagger_test.Circle: RegisterSubTypes requires a nil pointer to an interface, got swagger_test.Circle",
+		func() { swagger.RegisterSubTypes(Circle{}, "kind") })
+	assert.PanicsWithError(t, "swagger_test.Square: RegisterSubTypes: swagger_test.Square does not implement swagger_test.Shape",
+		func() { swagger.RegisterSubTypes((*Shape)(nil), "kind", Square{}) })
+
+	// the errors are returned by the E variants
+	_, err := swag.NewE(func(*swag.Builder) { swagger.RegisterSubTypes((*Shape)(nil), "kind", Square{}) })
+	var schemaErr *swagger.SchemaError
+	if assert.ErrorAs(t, err, &schemaErr) {
+		assert.Equal(t, reflect.TypeOf(Square{}), schemaErr.Type)
+	}
+}
@@ -16,6 +16,7 @@
 package swagger
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -23,29 +24,54 @@ import (
 	"strings"
 )
 
-func inspect(t reflect.Type, tag reflect.StructTag) Property {
-	if p, ok := customTypes[t]; ok {
+// inspect returns the property of t, with the values of t when it is an enum; enum tags take precedence
+func (r *Registry) inspect(t reflect.Type, tag reflect.StructTag) Property {
+	p := r.inspectType(t, tag)
+	if e, ok := r.enumOf(t); ok && len(p.Enum) == 0 {
+		p.Enum = e.values
+		p.EnumVarNames = e.names
+	}
+	return p
+}
+
+func (r *Registry) inspectType(t reflect.Type, tag reflect.StructTag) Property {
+	if p, ok := r.customTypes[t]; ok {
+		return p
+	}
+	if p, ok := providedProperty(t); ok {
+		return p
+	}
+	if p, _, ok := r.resolve(t); ok {
+		if !p.delegates(t) {
+			return p
+		}
+		resolved := r.inspect(p.GoType, tag)
+		resolved.Nullable = resolved.Nullable || p.Nullable
+		if p.Description != "" {
+			resolved.Description = p.Description
+		}
+		return resolved
+	}
+	if t.Kind() != reflect.String && marshalsText(t) {
+		// encoding/json writes text marshalers as strings, which the string tags apply to
+		p := r.inspect(reflect.TypeOf(""), tag)
+		p.GoType = t
 		return p
 	}
 
 	jsonTag := tag.Get("json")
 	defaultTag := tag.Get("default")
-	exampleTag := tag.Get("example")
 	formatTag := tag.Get("format")
 	minItemsTag := tag.Get("min_items")
 	maxItemsTag := tag.Get("max_items")
 	uniqueItemsTag := tag.Get("unique_items")
 	minLenTag := tag.Get("min_length")
 	maxLenTag := tag.Get("max_length")
-	minimumTag := tag.Get("minimum")
-	exclusiveMinimumTag := tag.Get("exclusive_minimum")
-	exclusiveMaximumTag := tag.Get("exclusive_maximum")
-	maximumTag := tag.Get("maximum")
 	patternTag := tag.Get("pattern")
 	enumTag := tag.Get("enum")
 
 	if t.Kind() == reflect.Ptr {
-		if p, ok := customTypes[t.Elem()]; ok {
+		if p, ok := r.customTypes[t.Elem()]; ok {
 			p.Nullable = true
 			return p
 		}
@@ -60,10 +86,6 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		return p
 	}
 
-	if exampleTag != "" {
-		p.Example = exampleTag
-	}
-
 	var err error
 	switch p.GoType.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
@@ -72,37 +94,10 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if defaultTag != "" {
 			p.Default, err = strconv.ParseInt(defaultTag, 10, 32)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert default tag value: %s", err))
-			}
-		}
-		if minimumTag != "" {
-			var min int64
-			min, err = strconv.ParseInt(minimumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert minimum tag value: %s", err))
-			}
-			p.Minimum = &min
-		}
-		if maximumTag != "" {
-			var max int64
-			max, err = strconv.ParseInt(maximumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert maximum tag value: %s", err))
-			}
-			p.Maximum = &max
-		}
-		if exclusiveMinimumTag != "" {
-			p.ExclusiveMinimum, err = strconv.ParseBool(exclusiveMinimumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_minimum tag value: %s", err))
-			}
-		}
-		if exclusiveMaximumTag != "" {
-			p.ExclusiveMaximum, err = strconv.ParseBool(exclusiveMaximumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_maximum tag value: %s", err))
+				panic(tagError("default", err))
 			}
 		}
+		inspectBounds(&p, tag)
 
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
 		p.Type = "integer"
@@ -110,37 +105,10 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if defaultTag != "" {
 			p.Default, err = strconv.ParseUint(defaultTag, 10, 32)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert default tag value: %s", err))
-			}
-		}
-		if minimumTag != "" {
-			var min int64
-			min, err = strconv.ParseInt(minimumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert minimum tag value: %s", err))
-			}
-			p.Minimum = &min
-		}
-		if maximumTag != "" {
-			var max int64
-			max, err = strconv.ParseInt(maximumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert maximum tag value: %s", err))
-			}
-			p.Maximum = &max
-		}
-		if exclusiveMinimumTag != "" {
-			p.ExclusiveMinimum, err = strconv.ParseBool(exclusiveMinimumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_minimum tag value: %s", err))
-			}
-		}
-		if exclusiveMaximumTag != "" {
-			p.ExclusiveMaximum, err = strconv.ParseBool(exclusiveMaximumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_maximum tag value: %s", err))
+				panic(tagError("default", err))
 			}
 		}
+		inspectBounds(&p, tag)
 
 	case reflect.Int64:
 		p.Type = "integer"
@@ -148,37 +116,10 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if defaultTag != "" {
 			p.Default, err = strconv.ParseInt(defaultTag, 10, 64)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert default tag value: %s", err))
-			}
-		}
-		if minimumTag != "" {
-			var min int64
-			min, err = strconv.ParseInt(minimumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert minimum tag value: %s", err))
-			}
-			p.Minimum = &min
-		}
-		if maximumTag != "" {
-			var max int64
-			max, err = strconv.ParseInt(maximumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert maximum tag value: %s", err))
-			}
-			p.Maximum = &max
-		}
-		if exclusiveMinimumTag != "" {
-			p.ExclusiveMinimum, err = strconv.ParseBool(exclusiveMinimumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_minimum tag value: %s", err))
-			}
-		}
-		if exclusiveMaximumTag != "" {
-			p.ExclusiveMaximum, err = strconv.ParseBool(exclusiveMaximumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_maximum tag value: %s", err))
+				panic(tagError("default", err))
 			}
 		}
+		inspectBounds(&p, tag)
 
 	case reflect.Uint64:
 		p.Type = "integer"
@@ -186,37 +127,10 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if defaultTag != "" {
 			p.Default, err = strconv.ParseUint(defaultTag, 10, 64)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert default tag value: %s", err))
-			}
-		}
-		if minimumTag != "" {
-			var min int64
-			min, err = strconv.ParseInt(minimumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert minimum tag value: %s", err))
-			}
-			p.Minimum = &min
-		}
-		if maximumTag != "" {
-			var max int64
-			max, err = strconv.ParseInt(maximumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert maximum tag value: %s", err))
-			}
-			p.Maximum = &max
-		}
-		if exclusiveMinimumTag != "" {
-			p.ExclusiveMinimum, err = strconv.ParseBool(exclusiveMinimumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_minimum tag value: %s", err))
-			}
-		}
-		if exclusiveMaximumTag != "" {
-			p.ExclusiveMaximum, err = strconv.ParseBool(exclusiveMaximumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_maximum tag value: %s", err))
+				panic(tagError("default", err))
 			}
 		}
+		inspectBounds(&p, tag)
 
 	case reflect.Float64:
 		p.Type = "number"
@@ -224,37 +138,10 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if defaultTag != "" {
 			p.Default, err = strconv.ParseFloat(defaultTag, 64)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert default tag value: %s", err))
-			}
-		}
-		if minimumTag != "" {
-			var min int64
-			min, err = strconv.ParseInt(minimumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert minimum tag value: %s", err))
-			}
-			p.Minimum = &min
-		}
-		if maximumTag != "" {
-			var max int64
-			max, err = strconv.ParseInt(maximumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert maximum tag value: %s", err))
-			}
-			p.Maximum = &max
-		}
-		if exclusiveMinimumTag != "" {
-			p.ExclusiveMinimum, err = strconv.ParseBool(exclusiveMinimumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_minimum tag value: %s", err))
-			}
-		}
-		if exclusiveMaximumTag != "" {
-			p.ExclusiveMaximum, err = strconv.ParseBool(exclusiveMaximumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_maximum tag value: %s", err))
+				panic(tagError("default", err))
 			}
 		}
+		inspectBounds(&p, tag)
 
 	case reflect.Float32:
 		p.Type = "number"
@@ -262,44 +149,17 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if defaultTag != "" {
 			p.Default, err = strconv.ParseFloat(defaultTag, 32)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert default tag value: %s", err))
-			}
-		}
-		if minimumTag != "" {
-			var min int64
-			min, err = strconv.ParseInt(minimumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert minimum tag value: %s", err))
-			}
-			p.Minimum = &min
-		}
-		if maximumTag != "" {
-			var max int64
-			max, err = strconv.ParseInt(maximumTag, 10, 64)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert maximum tag value: %s", err))
-			}
-			p.Maximum = &max
-		}
-		if exclusiveMinimumTag != "" {
-			p.ExclusiveMinimum, err = strconv.ParseBool(exclusiveMinimumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_minimum tag value: %s", err))
-			}
-		}
-		if exclusiveMaximumTag != "" {
-			p.ExclusiveMaximum, err = strconv.ParseBool(exclusiveMaximumTag)
-			if err != nil {
-				panic(fmt.Errorf("Failed to convert exclusive_maximum tag value: %s", err))
+				panic(tagError("default", err))
 			}
 		}
+		inspectBounds(&p, tag)
 
 	case reflect.Bool:
 		p.Type = "boolean"
 		if defaultTag != "" {
 			p.Default, err = strconv.ParseBool(defaultTag)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert default tag value: %s", err))
+				panic(tagError("default", err))
 			}
 		}
 
@@ -329,47 +189,51 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if minLenTag != "" {
 			p.MinLength, err = strconv.Atoi(minLenTag)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert min tag value: %s", err))
+				panic(tagError("min_length", err))
 			}
 		}
 
 		if maxLenTag != "" {
 			p.MaxLength, err = strconv.Atoi(maxLenTag)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert max tag value: %s", err))
+				panic(tagError("max_length", err))
 			}
 		}
 
 		if patternTag != "" {
 			_, err := regexp.Compile(patternTag)
 			if err != nil {
-				panic(fmt.Errorf("Failed to compile regexp: %s", err))
+				panic(tagError("pattern", err))
 			}
 
 			p.Pattern = patternTag
 		}
 
 	case reflect.Struct:
-		name := makeName(p.GoType)
+		name := r.makeName(p.GoType)
 		p.Ref = makeRef(name)
 
 	case reflect.Ptr:
-		p := inspect(t.Elem(), tag)
+		p := r.inspect(t.Elem(), tag)
 		p.Nullable = true
 		return p
 
 	case reflect.Map:
 		p.Type = "object"
-		ap := inspect(t.Elem(), tag)
+		ap := r.inspect(t.Elem(), valuesTag(tag))
 		// map[string]interface{} is just an object, no need for additionalProperties
-		if ap.GoType.Kind() != reflect.Interface {
+		if ap.GoType.Kind() != reflect.Interface || ap.Ref != "" {
 			p.AdditionalProperties = &ap
 		}
 
 	case reflect.Interface:
+		if _, ok := r.subTypes[t]; ok {
+			p.Ref = makeRef(r.makeName(t))
+			return p
+		}
 		p.Type = "object"
 
-	case reflect.Slice:
+	case reflect.Slice, reflect.Array:
 		// For json.RawMessage
 		if p.GoType.PkgPath() == "encoding/json" && p.GoType.Name() == "RawMessage" {
 			p.Type = "object"
@@ -379,102 +243,190 @@ func inspect(t reflect.Type, tag reflect.StructTag) Property {
 		if minItemsTag != "" {
 			p.MinItems, err = strconv.Atoi(minItemsTag)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert min_items tag value: %s", err))
+				panic(tagError("min_items", err))
 			}
+		} else if t.Kind() == reflect.Array {
+			p.MinItems = t.Len()
 		}
 
 		if maxItemsTag != "" {
 			p.MaxItems, err = strconv.Atoi(maxItemsTag)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert max_items tag value: %s", err))
+				panic(tagError("max_items", err))
 			}
+		} else if t.Kind() == reflect.Array {
+			p.MaxItems = t.Len()
 		}
 		if uniqueItemsTag != "" {
 			p.UniqueItems, err = strconv.ParseBool(uniqueItemsTag)
 			if err != nil {
-				panic(fmt.Errorf("Failed to convert unique_items tag value: %s", err))
+				panic(tagError("unique_items", err))
 			}
 		}
 
 		p.Type = "array"
-		p.Items = &Items{}
-
-		p.GoType = t.Elem() // dereference the slice
-		switch p.GoType.Kind() {
-		case reflect.Ptr:
-			p.GoType = p.GoType.Elem()
-			name := makeName(p.GoType)
-			p.Items.Ref = makeRef(name)
-
-		case reflect.Struct:
-			name := makeName(p.GoType)
-			p.Items.Ref = makeRef(name)
-
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
-			p.Items.Type = "integer"
-			p.Items.Format = "int32"
-
-		case reflect.Int64, reflect.Uint64:
-			p.Items.Type = "integer"
-			p.Items.Format = "int64"
-
-		case reflect.Float64:
-			p.Items.Type = "number"
-			p.Items.Format = "double"
-
-		case reflect.Float32:
-			p.Items.Type = "number"
-			p.Items.Format = "float"
-
-		case reflect.String:
-			p.Items.Type = "string"
-			if formatTag != "" {
-				splits := strings.Split(formatTag, ",")
-				if splits[0] == "enum" {
-					for _, eVal := range splits[1:] {
-						p.Items.Enum = append(p.Items.Enum, strings.TrimSpace(eVal))
-					}
-				} else {
-					p.Items.Format = strings.TrimSpace(splits[0])
-				}
-			}
 
-			if enumTag != "" {
-				splits := strings.Split(enumTag, ",")
-				for _, eVal := range splits {
-					p.Items.Enum = append(p.Items.Enum, strings.TrimSpace(eVal))
-				}
-			}
+		items := r.inspect(t.Elem(), itemsTag(tag))
+		p.GoType = items.GoType // dereference the slice, define looks for definitions of the elements
+		if items.GoType.Kind() == reflect.Interface && items.Ref == "" {
+			// the items of []interface{} may be anything
+			p.Items = &Items{}
+		} else {
+			p.Items = items.asItems()
+		}
+	}
 
-			if minLenTag != "" {
-				p.Items.MinLength, err = strconv.Atoi(minLenTag)
-				if err != nil {
-					panic(fmt.Errorf("Failed to convert min tag value: %s", err))
-				}
-			}
+	if enumTag != "" && (p.Type == "integer" || p.Type == "number") {
+		for _, eVal := range strings.Split(enumTag, ",") {
+			p.Enum = append(p.Enum, tagValue(p, "enum", strings.TrimSpace(eVal)))
+		}
+	}
+	return p
+}
 
-			if maxLenTag != "" {
-				p.Items.MaxLength, err = strconv.Atoi(maxLenTag)
-				if err != nil {
-					panic(fmt.Errorf("Failed to convert max tag value: %s", err))
-				}
-			}
+// itemsTag keeps the tags that apply to the items of an array, rather than to the array itself
+func itemsTag(tag reflect.StructTag) reflect.StructTag {
+	var kept []string
+	for _, name := range []string{"format", "enum", "min_length", "max_length", "pattern"} {
+		if v, ok := tag.Lookup(name); ok {
+			kept = append(kept, fmt.Sprintf("%v:%q", name, v))
+		}
+	}
+	return reflect.StructTag(strings.Join(kept, " "))
+}
 
-			if patternTag != "" {
-				_, err := regexp.Compile(patternTag)
-				if err != nil {
-					panic(fmt.Errorf("Failed to compile regexp: %s", err))
-				}
+// valuesTag keeps the tags that apply to the values of a map; default and example hold the value of the map as a whole
+func valuesTag(tag reflect.StructTag) reflect.StructTag {
+	var kept []string
+	for _, name := range []string{
+		"json", "format", "enum", "min_length", "max_length", "pattern", "min_items", "max_items", "unique_items",
+		"minimum", "maximum", "exclusive_minimum", "exclusive_maximum", "multiple_of",
+	} {
+		if v, ok := tag.Lookup(name); ok {
+			kept = append(kept, fmt.Sprintf("%v:%q", name, v))
+		}
+	}
+	return reflect.StructTag(strings.Join(kept, " "))
+}
 
-				p.Items.Pattern = patternTag
-			}
+// asItems converts the property of the elements of an array into its items
+func (p Property) asItems() *Items {
+	return &Items{
+		Type:                 p.Type,
+		Default:              p.Default,
+		Format:               p.Format,
+		Enum:                 p.Enum,
+		EnumVarNames:         p.EnumVarNames,
+		Ref:                  p.Ref,
+		Items:                p.Items,
+		MinItems:             p.MinItems,
+		MaxItems:             p.MaxItems,
+		UniqueItems:          p.UniqueItems,
+		MinLength:            p.MinLength,
+		MaxLength:            p.MaxLength,
+		Minimum:              p.Minimum,
+		Maximum:              p.Maximum,
+		ExclusiveMinimum:     p.ExclusiveMinimum,
+		ExclusiveMaximum:     p.ExclusiveMaximum,
+		MultipleOf:           p.MultipleOf,
+		Pattern:              p.Pattern,
+		AdditionalProperties: p.AdditionalProperties,
+	}
+}
+
+// inspectBounds sets the minimum, maximum and multipleOf of a number from the tags
+func inspectBounds(p *Property, tag reflect.StructTag) {
+	var err error
+	if v := tag.Get("minimum"); v != "" {
+		var min float64
+		min, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			panic(tagError("minimum", err))
+		}
+		p.Minimum = &min
+	}
+	if v := tag.Get("maximum"); v != "" {
+		var max float64
+		max, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			panic(tagError("maximum", err))
 		}
+		p.Maximum = &max
 	}
+	if v := tag.Get("exclusive_minimum"); v != "" {
+		p.ExclusiveMinimum, err = strconv.ParseBool(v)
+		if err != nil {
+			panic(tagError("exclusive_minimum", err))
+		}
+	}
+	if v := tag.Get("exclusive_maximum"); v != "" {
+		p.ExclusiveMaximum, err = strconv.ParseBool(v)
+		if err != nil {
+			panic(tagError("exclusive_maximum", err))
+		}
+	}
+	if v := tag.Get("multiple_of"); v != "" {
+		var multipleOf float64
+		multipleOf, err = strconv.ParseFloat(v, 64)
+		if err == nil && multipleOf <= 0 {
+			err = fmt.Errorf("must be greater than 0, got %v", v)
+		}
+		if err != nil {
+			panic(tagError("multiple_of", err))
+		}
+		p.MultipleOf = &multipleOf
+	}
+}
 
+// inspectField inspects the field of the struct t, adding the type and field to a SchemaError raised
+func (r *Registry) inspectField(t reflect.Type, field reflect.StructField) Property {
+	defer func() {
+		if v := recover(); v != nil {
+			if err, ok := v.(*SchemaError); ok && err.Type == nil {
+				err.Type, err.Field = t, field.Name
+			}
+			panic(v)
+		}
+	}()
+	p := r.inspect(field.Type, field.Tag)
+	constrain(&p, validatorRules(field.Tag))
+	if v := field.Tag.Get("example"); v != "" {
+		p.Example = tagValue(p, "example", v)
+	}
+	if v := field.Tag.Get("default"); v != "" && p.Default == nil {
+		p.Default = tagValue(p, "default", v)
+	}
 	return p
 }
 
-func defineObject(v interface{}) Object {
+// tagValue parses the value of an example or default tag according to the type of the property; arrays, objects and
+// references take json literals
+func tagValue(p Property, tag, value string) interface{} {
+	var v interface{}
+	var err error
+	switch p.Type {
+	case "string":
+		return value
+	case "integer":
+		if p.GoType != nil && p.GoType.Kind() >= reflect.Uint && p.GoType.Kind() <= reflect.Uint64 {
+			v, err = strconv.ParseUint(value, 10, 64)
+		} else {
+			v, err = strconv.ParseInt(value, 10, 64)
+		}
+	case "number":
+		v, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		v, err = strconv.ParseBool(value)
+	default:
+		err = json.Unmarshal([]byte(value), &v)
+	}
+	if err != nil {
+		panic(tagError(tag, err))
+	}
+	return v
+}
+
+func (r *Registry) defineObject(v interface{}) Object {
 	var required []string
 
 	var t reflect.Type
@@ -485,9 +437,11 @@ func defineObject(v interface{}) Object {
 		t = reflect.TypeOf(v)
 	}
 
-	objectName := makeName(t)
+	objectName := r.makeName(t)
 
 	properties := map[string]Property{}
+	var order []string
+	var allOf []Object
 	isArray := t.Kind() == reflect.Slice
 
 	if isArray {
@@ -498,7 +452,7 @@ func defineObject(v interface{}) Object {
 	}
 
 	if t.Kind() != reflect.Struct {
-		p := inspect(t, "")
+		p := r.inspect(t, "")
 		return Object{
 			IsArray:              isArray,
 			GoType:               t,
@@ -520,10 +474,21 @@ func defineObject(v interface{}) Object {
 
 		// If anoynmous - embed it
 		if field.Anonymous {
-			obj := defineObject(reflect.New(field.Type).Interface())
+			if embedded := field.Type; r.EmbedAsAllOf {
+				if embedded.Kind() == reflect.Ptr {
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct {
+					allOf = append(allOf, Object{GoType: embedded, Ref: makeRef(r.makeName(embedded))})
+					continue
+				}
+			}
+
+			obj := r.defineObject(reflect.New(field.Type).Interface())
 			for k, v := range obj.Properties {
 				properties[k] = v
 			}
+			order = append(order, obj.PropertyOrder...)
 		} else {
 			// determine the json name of the field
 			name := strings.TrimSpace(field.Tag.Get("json"))
@@ -543,107 +508,132 @@ func defineObject(v interface{}) Object {
 				continue
 			}
 
-			// determine if this field is required or not
-			if v := field.Tag.Get("required"); v == "true" {
+			// determine if this field is required or not, from the required tag or the go-playground/validator
+			// validate and binding tags
+			if field.Tag.Get("required") == "true" || requiredRule(validatorRules(field.Tag)) {
 				if required == nil {
 					required = []string{}
 				}
 				required = append(required, name)
 			}
 
-			// support go-playground/validator binding tags
-			if v := field.Tag.Get("binding"); v != "" {
-				parts := strings.Split(v, ",") // "gt=0,dive,len=1,dive,required"
-				for _, a := range parts {
-					if a == "required" {
-						if required == nil {
-							required = []string{}
-						}
-						required = append(required, name)
-					}
-				}
+			p := r.inspectField(t, field)
+			if description := r.fieldDoc(t, field); description != "" {
+				p.Description = description
 			}
 
-			p := inspect(field.Type, field.Tag)
-
 			properties[name] = p
+			order = append(order, name)
 		}
 	}
 
-	return Object{
-		IsArray:    isArray,
-		GoType:     t,
-		Type:       "object",
-		Name:       objectName,
-		Required:   required,
-		Properties: properties,
+	obj := Object{
+		IsArray:     isArray,
+		GoType:      t,
+		Type:        "object",
+		Description: r.typeDoc(t),
+		Name:        objectName,
+		Required:    required,
+		Properties:  properties,
+	}
+	if r.StructFieldOrder {
+		obj.PropertyOrder = order
+	}
+	customize(t, &obj)
+
+	if base, ok := r.baseTypes[t]; ok {
+		obj.removeProperty(r.subTypes[base].discriminator)
+		allOf = append([]Object{{GoType: base, Ref: makeRef(r.makeName(base))}}, allOf...)
+	}
+	if len(allOf) > 0 {
+		obj = obj.extend(allOf)
 	}
+
+	return obj
 }
 
-func define(v interface{}) map[string]Object {
+func (r *Registry) define(v interface{}) map[string]Object {
+	renamed := len(r.definitionNames)
 	objMap := map[string]Object{}
 
-	obj := defineObject(v)
-	objMap[obj.Name] = obj
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	if _, _, resolved := r.resolve(t); resolved {
+		r.addDefinitions(objMap, t)
+	} else {
+		obj := r.defineObject(v)
+		objMap[obj.Name] = obj
+	}
 
 	dirty := true
 
 	for dirty {
 		dirty = false
 		for i, d := range objMap {
-			if item, ok := customTypes[d.GoType]; ok {
+			if item, ok := r.customTypes[d.GoType]; ok {
 				tmp := objMap[i]
 				tmp.AdditionalProperties = item.AdditionalProperties != nil
 				objMap[i] = tmp
 				continue
 			}
-			for _, p := range d.Properties {
-				if item, ok := customTypes[d.GoType]; ok {
+			for _, p := range d.allProperties() {
+				if item, ok := r.customTypes[d.GoType]; ok {
 					tmp := objMap[i]
 					tmp.AdditionalProperties = item.AdditionalProperties != nil
 					objMap[i] = tmp
 					continue
 				}
-				if p.GoType.Kind() == reflect.Struct {
-					name := makeName(p.GoType)
-					if _, exists := objMap[name]; !exists {
-						child := defineObject(p.GoType)
-						objMap[child.Name] = child
-						dirty = true
-					}
+				if rt := referencedType(p); rt != nil && r.addDefinitions(objMap, rt) {
+					dirty = true
 				}
-				if p.AdditionalProperties != nil {
-					var t reflect.Type
-					switch value := p.AdditionalProperties.(type) {
-					case reflect.Type:
-						t = value
-					default:
-						t = reflect.TypeOf(p.AdditionalProperties)
-					}
-					if t.Kind() == reflect.Ptr {
-						ap := p.AdditionalProperties.(*Property)
-						name := makeName(ap.GoType)
-						if _, exists := objMap[name]; !exists {
-							child := defineObject(ap.GoType)
-							objMap[child.Name] = child
-							dirty = true
-						}
-					}
+			}
+			for _, parent := range d.AllOf {
+				if parent.Ref != "" && parent.GoType != nil && r.addDefinitions(objMap, parent.GoType) {
+					dirty = true
 				}
 			}
 		}
 	}
 
+	// a name collision renamed types that may already be referenced, so start over with the new names
+	if len(r.definitionNames) != renamed {
+		r.reset()
+		return r.define(v)
+	}
+
 	return objMap
 }
 
-// MakeSchema takes struct or pointer to a struct and returns a Schema instance suitable for use by the swagger doc
+// MakeSchema takes struct or pointer to a struct and returns a Schema instance suitable for use by the swagger doc;
+// other types, like []string or map[string]int, are described inline
 func MakeSchema(prototype interface{}) *Schema {
+	return defaultRegistry().MakeSchema(prototype)
+}
+
+// MakeSchema returns the Schema of a struct or pointer to a struct, named by the registry; a nil registry uses the
+// package level settings
+func (r *Registry) MakeSchema(prototype interface{}) *Schema {
+	r = r.orDefault()
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	t, ok := prototype.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(prototype)
+	}
+	if r.inlined(t) {
+		schema := r.inlineSchema(t)
+		schema.Prototype = prototype
+		return schema
+	}
+
 	schema := &Schema{
 		Prototype: prototype,
 	}
 
-	obj := defineObject(prototype)
+	obj := r.defineObject(prototype)
 	if obj.IsArray {
 		schema.Type = "array"
 		schema.Items = &Items{
@@ -656,3 +646,47 @@ func MakeSchema(prototype interface{}) *Schema {
 
 	return schema
 }
+
+// inlined reports whether values of t are described by an inline schema rather than a definition; only structs,
+// registered interfaces, and slices of them, have definitions
+func (r *Registry) inlined(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		// unless the struct is described by a property of its own
+		_, custom := r.customTypes[t]
+		_, provided := providedProperty(t)
+		_, _, resolved := r.resolve(t)
+		return custom || provided || resolved || marshalsText(t)
+	case reflect.Interface:
+		_, ok := r.subTypes[t]
+		return !ok
+	default:
+		return true
+	}
+}
+
+// inlineSchema describes t, which is not a struct, with the property it would have as a field
+func (r *Registry) inlineSchema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	p := r.inspect(t, "")
+	return &Schema{
+		Type:                 p.Type,
+		Format:               p.Format,
+		Ref:                  p.Ref,
+		Items:                p.Items,
+		AdditionalProperties: p.AdditionalProperties,
+	}
+}
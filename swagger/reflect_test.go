@@ -15,9 +15,15 @@ package swagger
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
 	"testing"
 	"time"
 
@@ -140,7 +146,7 @@ func TestNotStructDefine(t *testing.T) {
 	assert.False(t, obj.IsArray)
 	assert.Equal(t, "object", obj.Type)
 	assert.Equal(t, "", obj.Format)
-	assert.True(t, obj.AdditionalProperties)
+	assert.Equal(t, true, obj.AdditionalProperties)
 }
 
 func TestHonorJsonIgnore(t *testing.T) {
@@ -176,3 +182,289 @@ func TestCustomTypes(t *testing.T) {
 	assert.Contains(t, obj.Properties, "testTime")
 	assert.EqualValues(t, "string", obj.Properties["testTime"].Type)
 }
+
+func TestTypedExamples(t *testing.T) {
+	type Tagged struct {
+		Name    string            `json:"name" example:"rex"`
+		Age     int               `json:"age" example:"3" default:"1"`
+		Size    uint8             `json:"size" example:"200"`
+		Weight  float64           `json:"weight" example:"4.5"`
+		Active  bool              `json:"active" example:"true" default:"false"`
+		Tags    []string          `json:"tags" example:"[\"small\",\"brown\"]"`
+		Labels  map[string]string `json:"labels" example:"{\"coat\":\"short\"}"`
+		Nothing string            `json:"nothing"`
+	}
+
+	obj := defineObject(Tagged{})
+
+	assert.Equal(t, "rex", obj.Properties["name"].Example)
+	assert.Equal(t, int64(3), obj.Properties["age"].Example)
+	assert.Equal(t, int64(1), obj.Properties["age"].Default)
+	assert.Equal(t, uint64(200), obj.Properties["size"].Example)
+	assert.Equal(t, 4.5, obj.Properties["weight"].Example)
+	assert.Equal(t, true, obj.Properties["active"].Example)
+	assert.Equal(t, false, obj.Properties["active"].Default)
+	assert.Equal(t, []interface{}{"small", "brown"}, obj.Properties["tags"].Example)
+	assert.Equal(t, map[string]interface{}{"coat": "short"}, obj.Properties["labels"].Example)
+	assert.Nil(t, obj.Properties["nothing"].Example)
+
+	data, err := json.Marshal(obj.Properties["age"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"integer","format":"int32","example":3,"default":1}`, string(data))
+}
+
+func TestMapDefaults(t *testing.T) {
+	type Tallies struct {
+		Counts map[string]int    `json:"counts" default:"{\"a\":1}" example:"{\"b\":2}" minimum:"0"`
+		Flags  map[string]bool   `json:"flags" default:"{}"`
+		Sizes  map[string]uint16 `json:"sizes" default:"{\"small\":1}"`
+	}
+
+	_, err := MakeSchemaE(Tallies{})
+	assert.Nil(t, err)
+
+	def := defineObject(Tallies{})
+	counts := def.Properties["counts"]
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, counts.Default)
+	assert.Equal(t, map[string]interface{}{"b": 2.0}, counts.Example)
+	if values, ok := counts.AdditionalProperties.(*Property); assert.True(t, ok) {
+		assert.Equal(t, "integer", values.Type)
+		assert.Nil(t, values.Default)
+		assert.Equal(t, 0.0, *values.Minimum)
+	}
+	assert.Equal(t, map[string]interface{}{}, def.Properties["flags"].Default)
+	assert.Equal(t, map[string]interface{}{"small": 1.0}, def.Properties["sizes"].Default)
+}
+
+func TestInvalidExample(t *testing.T) {
+	type BadExample struct {
+		Age int `json:"age" example:"three"`
+	}
+
+	_, err := MakeSchemaE(BadExample{})
+
+	if assert.Error(t, err) {
+		schemaErr, ok := err.(*SchemaError)
+		if assert.True(t, ok) {
+			assert.Equal(t, "Age", schemaErr.Field)
+			assert.Equal(t, "example", schemaErr.Tag)
+		}
+	}
+}
+
+func TestFloatBounds(t *testing.T) {
+	type Pricing struct {
+		Price   float64 `json:"price" minimum:"0.5" maximum:"99.99" multiple_of:"0.01"`
+		Percent int     `json:"percent" minimum:"0" maximum:"100" exclusive_maximum:"true"`
+	}
+
+	obj := defineObject(Pricing{})
+
+	price := obj.Properties["price"]
+	assert.Equal(t, 0.5, *price.Minimum)
+	assert.Equal(t, 99.99, *price.Maximum)
+	assert.Equal(t, 0.01, *price.MultipleOf)
+
+	data, err := json.Marshal(obj.Properties["percent"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"integer","format":"int32","minimum":0,"maximum":100,"exclusiveMaximum":true}`, string(data))
+
+	type BadMultiple struct {
+		Price float64 `json:"price" multiple_of:"0"`
+	}
+	_, err = MakeSchemaE(BadMultiple{})
+	assert.EqualError(t, err, `swagger.BadMultiple: field Price: tag multiple_of: must be greater than 0, got 0`)
+}
+
+type Stop struct {
+	Name string `json:"name"`
+}
+
+type Leg struct {
+	Distance float64 `json:"distance"`
+}
+
+type Key [2]byte
+
+func (k Key) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%x", k[:])), nil
+}
+
+type Route struct {
+	Coordinates [][]float64       `json:"coordinates"`
+	Stops       []map[string]Stop `json:"stops"`
+	Box         [4]int            `json:"box"`
+	Legs        []*[]Leg          `json:"legs"`
+	ByID        map[int]Stop      `json:"byId"`
+	ByKey       map[Key][]Leg     `json:"byKey"`
+	Codes       [][]string        `json:"codes" enum:"a,b" min_items:"1"`
+	Anything    []interface{}     `json:"anything"`
+}
+
+func TestNestedContainers(t *testing.T) {
+	r := NewRegistry()
+	r.UsePackageName = false
+
+	objMap := r.define(Route{})
+	assert.Len(t, objMap, 3)
+	assert.Contains(t, objMap, "Stop")
+	assert.Contains(t, objMap, "Leg")
+
+	props := objMap["Route"].Properties
+	data, err := json.Marshal(props)
+	assert.Nil(t, err)
+
+	actual := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &actual))
+	assert.Equal(t, map[string]interface{}{
+		"coordinates": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "number", "format": "double"}},
+		},
+		"stops": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"$ref": "#/definitions/Stop"},
+			},
+		},
+		"box": map[string]interface{}{
+			"type":     "array",
+			"minItems": float64(4),
+			"maxItems": float64(4),
+			"items":    map[string]interface{}{"type": "integer", "format": "int32"},
+		},
+		"legs": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/definitions/Leg"}},
+		},
+		"byId": map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"$ref": "#/definitions/Stop"},
+		},
+		"byKey": map[string]interface{}{
+			"type": "object",
+			"additionalProperties": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"$ref": "#/definitions/Leg"},
+			},
+		},
+		"codes": map[string]interface{}{
+			"type":     "array",
+			"minItems": float64(1),
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}},
+			},
+		},
+		"anything": map[string]interface{}{"type": "array", "items": map[string]interface{}{}},
+	}, actual)
+}
+
+type Level int
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte([]string{"low", "high"}[l]), nil
+}
+
+type Point struct {
+	X, Y int
+}
+
+func (p Point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.X, p.Y)), nil
+}
+
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"x": p.X, "y": p.Y})
+}
+
+type Catalog struct {
+	Data     []byte         `json:"data"`
+	Timeout  time.Duration  `json:"timeout"`
+	Amount   json.Number    `json:"amount"`
+	Balance  *big.Int       `json:"balance"`
+	Ratio    big.Float      `json:"ratio"`
+	Address  net.IP         `json:"address"`
+	Gateway  netip.Addr     `json:"gateway"`
+	Level    Level          `json:"level" enum:"low,high"`
+	Levels   []Level        `json:"levels"`
+	Location Point          `json:"location"`
+	Name     sql.NullString `json:"name"`
+}
+
+func TestBuiltinTypes(t *testing.T) {
+	r := NewRegistry()
+	r.UsePackageName = false
+
+	objMap := r.define(Catalog{})
+	props := objMap["Catalog"].Properties
+
+	assert.Equal(t, "string", props["data"].Type)
+	assert.Equal(t, "byte", props["data"].Format)
+	assert.Equal(t, "integer", props["timeout"].Type)
+	assert.Equal(t, "duration in nanoseconds", props["timeout"].Description)
+	assert.Equal(t, "number", props["amount"].Type)
+	assert.Equal(t, "integer", props["balance"].Type)
+	assert.True(t, props["balance"].Nullable)
+
+	// text marshalers are strings
+	for _, name := range []string{"ratio", "address", "gateway", "level"} {
+		assert.Equal(t, "string", props[name].Type, name)
+		assert.Empty(t, props[name].Ref, name)
+	}
+	assert.Equal(t, []interface{}{"low", "high"}, props["level"].Enum)
+	assert.Equal(t, &Items{Type: "string"}, props["levels"].Items)
+
+	// json.Marshaler takes precedence, and sql.NullString is written as an object by encoding/json
+	assert.Equal(t, "#/definitions/Point", props["location"].Ref)
+	assert.Equal(t, "#/definitions/NullString", props["name"].Ref)
+	assert.Len(t, objMap, 3)
+
+	schema := r.MakeSchema(time.Time{})
+	assert.Equal(t, "string", schema.Type)
+	assert.Equal(t, "date-time", schema.Format)
+	assert.Empty(t, schema.Ref)
+
+	schema = r.MakeSchema([]byte{})
+	assert.Equal(t, "string", schema.Type)
+	assert.Equal(t, "byte", schema.Format)
+}
+
+type Nullables struct {
+	Name     sql.NullString  `json:"name"`
+	Count    sql.NullInt64   `json:"count"`
+	Score    sql.NullFloat64 `json:"score"`
+	Active   sql.NullBool    `json:"active"`
+	Deleted  sql.NullTime    `json:"deleted"`
+	Homepage *url.URL        `json:"homepage"`
+	Mirror   url.URL         `json:"mirror"`
+}
+
+func TestRegisterSQLNullTypes(t *testing.T) {
+	r := NewRegistry()
+	r.UsePackageName = false
+	r.RegisterSQLNullTypes()
+	r.RegisterURLType()
+
+	objMap := r.define(Nullables{})
+	assert.Len(t, objMap, 1)
+
+	props := objMap["Nullables"].Properties
+	assert.Equal(t, Property{GoType: reflect.TypeOf(sql.NullString{}), Type: "string", Nullable: true}, props["name"])
+	assert.Equal(t, "int64", props["count"].Format)
+	assert.Equal(t, "number", props["score"].Type)
+	assert.Equal(t, "boolean", props["active"].Type)
+	assert.Equal(t, "date-time", props["deleted"].Format)
+	for _, name := range []string{"count", "score", "active", "deleted"} {
+		assert.True(t, props[name].Nullable, name)
+	}
+
+	assert.Equal(t, "uri", props["homepage"].Format)
+	assert.True(t, props["homepage"].Nullable)
+	assert.Equal(t, "uri", props["mirror"].Format)
+	assert.False(t, props["mirror"].Nullable)
+
+	// the default registry is not affected
+	assert.Contains(t, defineObject(Nullables{}).Properties["name"].Ref, "NullString")
+}
@@ -0,0 +1,191 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Registry owns what is used to generate definitions from go types: the custom types and subtypes, the naming policy
+// and a cache of the definitions already generated. The package level settings, e.g. UsePackageName and
+// RegisterCustomType, make up the default registry; give an API and its endpoints their own registry to generate them
+// with different settings, e.g. a public and an admin API in one binary. Settings should not be changed once
+// definitions have been generated
+type Registry struct {
+	// UsePackageName adds the package prefix to generated definition names
+	UsePackageName bool
+
+	// StripPackagePrefixes removes leading strings from long package names, eg github.com/some-ORG/
+	StripPackagePrefixes []string
+
+	// StructFieldOrder records the struct field order on generated definitions, to render their properties in that
+	// order rather than sorted by name
+	StructFieldOrder bool
+
+	// EmbedAsAllOf references the definitions of embedded structs with allOf rather than copying their fields
+	EmbedAsAllOf bool
+
+	// NameCollisions is the policy applied when different types are given the same definition name
+	NameCollisions CollisionPolicy
+
+	// CollisionName, when set, names the types whose definition names collide under QualifyCollisions
+	CollisionName func(t reflect.Type) string
+
+	mux             *sync.Mutex
+	customTypes     map[reflect.Type]Property
+	subTypes        map[reflect.Type]polymorphism
+	baseTypes       map[reflect.Type]reflect.Type
+	definitionNames map[reflect.Type]string
+	resolvers       *[]TypeResolver
+	enums           map[reflect.Type]enum
+	docComments     map[string]string
+
+	// definitions caches the definitions generated for each prototype type; nil disables the cache
+	definitions map[reflect.Type]map[string]Object
+}
+
+// globalMux guards the package level custom types, type resolvers, enums, subtypes and doc comments
+var globalMux sync.Mutex
+
+// NewRegistry creates a registry whose settings, custom types, type resolvers, enums, subtypes and doc comments start
+// as copies of the package level ones
+func NewRegistry() *Registry {
+	globalMux.Lock()
+	defer globalMux.Unlock()
+
+	r := defaultRegistry()
+	r.mux = &sync.Mutex{}
+	r.customTypes = map[reflect.Type]Property{}
+	for t, p := range customTypes {
+		r.customTypes[t] = p
+	}
+	r.enums = map[reflect.Type]enum{}
+	for t, e := range enums {
+		r.enums[t] = e
+	}
+	r.subTypes = map[reflect.Type]polymorphism{}
+	for t, poly := range subTypes {
+		r.subTypes[t] = poly
+	}
+	r.baseTypes = map[reflect.Type]reflect.Type{}
+	for t, base := range baseTypes {
+		r.baseTypes[t] = base
+	}
+	r.docComments = map[string]string{}
+	for key, text := range docComments {
+		r.docComments[key] = text
+	}
+	r.StripPackagePrefixes = append([]string(nil), StripPackagePrefixes...)
+	rs := append([]TypeResolver(nil), resolvers...)
+	r.resolvers = &rs
+	r.definitionNames = map[reflect.Type]string{}
+	r.definitions = map[reflect.Type]map[string]Object{}
+	return r
+}
+
+// defaultRegistry returns the registry made of the package level settings; it has no definition cache, as the
+// settings may change between calls
+func defaultRegistry() *Registry {
+	return &Registry{
+		UsePackageName:       UsePackageName,
+		StripPackagePrefixes: StripPackagePrefixes,
+		StructFieldOrder:     StructFieldOrder,
+		EmbedAsAllOf:         EmbedAsAllOf,
+		NameCollisions:       NameCollisions,
+		CollisionName:        CollisionName,
+		mux:                  &globalMux,
+		customTypes:          customTypes,
+		subTypes:             subTypes,
+		baseTypes:            baseTypes,
+		definitionNames:      map[reflect.Type]string{},
+		resolvers:            &resolvers,
+		enums:                enums,
+		docComments:          docComments,
+	}
+}
+
+func (r *Registry) orDefault() *Registry {
+	if r == nil {
+		return defaultRegistry()
+	}
+	return r
+}
+
+// named returns a view of the registry that records the names given to colliding types in names, the rename table of
+// the api being built, so that the renames of one api do not apply to the next
+func (r *Registry) named(names map[reflect.Type]string) *Registry {
+	view := *r
+	view.definitionNames = names
+	return &view
+}
+
+// reset empties the definition cache after a change to the registry
+func (r *Registry) reset() {
+	if r.definitions != nil {
+		r.definitions = map[reflect.Type]map[string]Object{}
+	}
+}
+
+// definitionsOf returns the definitions of the prototype and of the types it refers to, from the cache if possible
+func (r *Registry) definitionsOf(prototype interface{}) map[string]Object {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	t, ok := prototype.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(prototype)
+	}
+	// the cached definitions are named without renames
+	cache := r.definitions != nil && t != nil && len(r.definitionNames) == 0
+	if objMap, ok := r.definitions[t]; ok && cache {
+		return objMap
+	}
+
+	objMap := r.define(prototype)
+	if cache && len(r.definitionNames) == 0 {
+		r.definitions[t] = objMap
+	}
+	return objMap
+}
+
+// remakeSchemas makes the schemas of the endpoint's body and responses again, with the names given by the registry
+func (r *Registry) remakeSchemas(e *Endpoint) {
+	remake := func(s *Schema) *Schema {
+		if s == nil || s.Prototype == nil || s.Type == "file" {
+			return s
+		}
+		return r.MakeSchema(s.Prototype)
+	}
+
+	for i, p := range e.Parameters {
+		e.Parameters[i].Schema = remake(p.Schema)
+	}
+	for code, response := range e.Responses {
+		response.Schema = remake(response.Schema)
+		e.Responses[code] = response
+	}
+}
+
+func define(v interface{}) map[string]Object {
+	return defaultRegistry().define(v)
+}
+
+func defineObject(v interface{}) Object {
+	return defaultRegistry().defineObject(v)
+}
+
+func makeName(t reflect.Type) string {
+	return defaultRegistry().makeName(t)
+}
@@ -0,0 +1,149 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Event struct {
+	At time.Time `json:"at"`
+}
+
+func TestRegistry(t *testing.T) {
+	public := swagger.NewRegistry()
+	public.UsePackageName = false
+	admin := swagger.NewRegistry()
+	admin.UsePackageName = true
+	admin.StripPackagePrefixes = []string{"github.com/miketonks/swag/"}
+	admin.RegisterCustomType(time.Time{}, swagger.Property{Type: "integer", Format: "int64"})
+
+	newAPI := func(r *swagger.Registry) *swagger.API {
+		return swag.New(
+			swag.Endpoints(endpoint.New("get", "/event", "Get event", endpoint.Response(200, Event{}, "ok"))),
+			swag.Registry(r),
+		)
+	}
+
+	for i := 0; i < 4; i++ {
+		t.Run("parallel", func(t *testing.T) {
+			t.Parallel()
+
+			api := newAPI(public)
+			assert.Equal(t, "#/definitions/Event", api.Paths["/event"].Get.Responses["200"].Schema.Ref)
+			assert.Equal(t, "date-time", api.Definitions["Event"].Properties["at"].Format)
+
+			api = newAPI(admin)
+			assert.Equal(t, "#/definitions/swagger_test_Event", api.Paths["/event"].Get.Responses["200"].Schema.Ref)
+			assert.Equal(t, "int64", api.Definitions["swagger_test_Event"].Properties["at"].Format)
+		})
+	}
+}
+
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+type Monetary interface {
+	Currency() string
+}
+
+type USD struct {
+	Cents int64
+}
+
+func (USD) Currency() string { return "USD" }
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Order struct {
+	Note     Optional[string]    `json:"note"`
+	Ship     Optional[Address]   `json:"ship"`
+	Stops    []Optional[Address] `json:"stops"`
+	Total    USD                 `json:"total"`
+	Discount Monetary            `json:"discount"`
+}
+
+func TestTypeResolvers(t *testing.T) {
+	moneyType := reflect.TypeOf((*Monetary)(nil)).Elem()
+	money := swagger.Object{
+		Name: "Money",
+		Type: "object",
+		Properties: map[string]swagger.Property{
+			"amount":   {Type: "string", Pattern: "^\\d+\\.\\d\\d$"},
+			"currency": {Type: "string"},
+		},
+	}
+
+	r := swagger.NewRegistry()
+	r.UsePackageName = false
+	r.RegisterTypeResolver(func(t reflect.Type) (swagger.Property, []swagger.Object, bool) {
+		if t.Kind() != reflect.Struct || !strings.HasPrefix(t.Name(), "Optional[") {
+			return swagger.Property{}, nil, false
+		}
+		return swagger.Property{GoType: t.Field(0).Type, Nullable: true}, nil, true
+	})
+	r.RegisterTypeResolver(func(t reflect.Type) (swagger.Property, []swagger.Object, bool) {
+		if !t.Implements(moneyType) {
+			return swagger.Property{}, nil, false
+		}
+		return swagger.Property{Ref: "#/definitions/Money"}, []swagger.Object{money}, true
+	})
+
+	api := swag.New(
+		swag.Endpoints(
+			endpoint.New("post", "/order", "Place order",
+				endpoint.Body(Order{}, "order", true),
+				endpoint.Response(200, Optional[Address]{}, "ok"),
+			),
+			endpoint.New("get", "/total", "Get total", endpoint.Response(200, USD{}, "ok")),
+		),
+		swag.Registry(r),
+	)
+
+	order := api.Definitions["Order"]
+	assert.Equal(t, swagger.Property{Type: "string", Nullable: true}, clearGoType(order.Properties["note"]))
+	assert.Equal(t, swagger.Property{Ref: "#/definitions/Address", Nullable: true}, clearGoType(order.Properties["ship"]))
+	assert.Equal(t, &swagger.Items{Ref: "#/definitions/Address"}, order.Properties["stops"].Items)
+	assert.Equal(t, "#/definitions/Money", order.Properties["total"].Ref)
+	assert.Equal(t, "#/definitions/Money", order.Properties["discount"].Ref)
+
+	assert.Equal(t, "#/definitions/Address", api.Paths["/order"].Post.Responses["200"].Schema.Ref)
+	assert.Equal(t, "#/definitions/Money", api.Paths["/total"].Get.Responses["200"].Schema.Ref)
+
+	assert.Len(t, api.Definitions, 3)
+	assert.Equal(t, money.Properties, api.Definitions["Money"].Properties)
+	assert.Contains(t, api.Definitions, "Address")
+
+	// the resolvers belong to the registry they were added to
+	other := swagger.NewRegistry()
+	other.UsePackageName = false
+	assert.Equal(t, "#/definitions/USD", other.MakeSchema(USD{}).Ref)
+}
+
+func clearGoType(p swagger.Property) swagger.Property {
+	p.GoType = nil
+	return p
+}
@@ -24,6 +24,10 @@ import (
 // UsePackageName can be set to true to add package prefix of generated definition names
 var UsePackageName = false
 
+// StructFieldOrder can be set to true to render the properties of definitions generated afterwards in struct field
+// order rather than sorted by name
+var StructFieldOrder = false
+
 // StripPackagePrefixes can be set to remove leading strings from long package names, eg github.com/some-ORG/
 // So github.com/some-ORG/repo/types.Pet becomes repo/types.Pet
 var StripPackagePrefixes []string
@@ -37,10 +41,10 @@ type parsedType interface {
 
 	// handlePackageName handles all transformations on package names.
 	//
-	// If UsePackageName is true, any non-builtin type with an empty package will be set to use `packageName`; if UsePackageName is false, the reverse transformation is applied.
-	// Any package which matches any entry in StripPackagePrefixes will have that prefix stripped.
+	// If the registry's UsePackageName is true, any non-builtin type with an empty package will be set to use `packageName`; if UsePackageName is false, the reverse transformation is applied.
+	// Any package which matches any entry in the registry's StripPackagePrefixes will have that prefix stripped.
 	// All generic arguments will be transformed recursively.
-	handlePackageName(string)
+	handlePackageName(string, *Registry)
 }
 
 var _ parsedType = &parsedNamed{}
@@ -77,8 +81,8 @@ func (ty parsedNamed) isBuiltin() bool {
 	}
 	return false
 }
-func (ty *parsedNamed) handlePackageName(packageName string) {
-	if UsePackageName {
+func (ty *parsedNamed) handlePackageName(packageName string, r *Registry) {
+	if r.UsePackageName {
 		if ty.pkg == "" && !ty.isBuiltin() {
 			ty.pkg = packageName
 		}
@@ -88,7 +92,7 @@ func (ty *parsedNamed) handlePackageName(packageName string) {
 		}
 	}
 
-	for _, pfx := range StripPackagePrefixes {
+	for _, pfx := range r.StripPackagePrefixes {
 		if strings.HasPrefix(ty.pkg, pfx) {
 			ty.pkg = strings.TrimPrefix(ty.pkg, pfx)
 			break
@@ -96,7 +100,7 @@ func (ty *parsedNamed) handlePackageName(packageName string) {
 	}
 
 	for _, g := range ty.generic {
-		g.handlePackageName(packageName)
+		g.handlePackageName(packageName, r)
 	}
 }
 
@@ -110,9 +114,9 @@ type parsedMap struct {
 func (ty parsedMap) String() string {
 	return fmt.Sprintf("map_%s_to_%s", ty.key, ty.value)
 }
-func (ty *parsedMap) handlePackageName(packageName string) {
-	ty.key.handlePackageName(packageName)
-	ty.value.handlePackageName(packageName)
+func (ty *parsedMap) handlePackageName(packageName string, r *Registry) {
+	ty.key.handlePackageName(packageName, r)
+	ty.value.handlePackageName(packageName, r)
 }
 
 var _ parsedType = &parsedSlice{}
@@ -129,8 +133,8 @@ func (ty parsedSlice) String() string {
 		return fmt.Sprintf("arr_%s", ty.elem)
 	}
 }
-func (ty *parsedSlice) handlePackageName(packageName string) {
-	ty.elem.handlePackageName(packageName)
+func (ty *parsedSlice) handlePackageName(packageName string, r *Registry) {
+	ty.elem.handlePackageName(packageName, r)
 }
 
 func parseArrayCount(input string) (string, string) {
@@ -153,8 +157,8 @@ type parsedPtr struct {
 func (ty parsedPtr) String() string {
 	return fmt.Sprintf("ptr_%s", ty.elem)
 }
-func (ty *parsedPtr) handlePackageName(packageName string) {
-	ty.elem.handlePackageName(packageName)
+func (ty *parsedPtr) handlePackageName(packageName string, r *Registry) {
+	ty.elem.handlePackageName(packageName, r)
 }
 
 // parseType parses a type into a parsedType.
@@ -253,8 +257,8 @@ type reflectType interface {
 	String() string
 }
 
-func makeName(t reflect.Type) string {
-	ty := reflectParseType(t)
+func (r *Registry) makeName(t reflect.Type) string {
+	ty := r.reflectParseType(t)
 	name := ty.String()
 
 	name = strings.TrimSpace(name)
@@ -265,33 +269,39 @@ func makeName(t reflect.Type) string {
 	return name
 }
 
-func reflectParseType(t reflect.Type) parsedType {
+func (r *Registry) reflectParseType(t reflect.Type) parsedType {
+	if name, ok := r.definitionNames[t]; ok {
+		return &parsedNamed{name: name}
+	}
+	if name, ok := declaredName(t); ok {
+		return &parsedNamed{name: name}
+	}
 	if t.Name() != "" {
 		p, rest := parseType(t.Name())
 		if rest != "" {
 			panic(fmt.Sprintf("failed to parse type %q, rest=%q", t.Name(), rest))
 		}
-		p.handlePackageName(t.PkgPath())
+		p.handlePackageName(t.PkgPath(), r)
 		return p
 	}
 	switch t.Kind() {
 	case reflect.Array:
 		return &parsedSlice{
 			count: fmt.Sprintf("%d", t.Len()),
-			elem:  reflectParseType(t.Elem()),
+			elem:  r.reflectParseType(t.Elem()),
 		}
 	case reflect.Slice:
 		return &parsedSlice{
-			elem: reflectParseType(t.Elem()),
+			elem: r.reflectParseType(t.Elem()),
 		}
 	case reflect.Map:
 		return &parsedMap{
-			key:   reflectParseType(t.Key()),
-			value: reflectParseType(t.Elem()),
+			key:   r.reflectParseType(t.Key()),
+			value: r.reflectParseType(t.Elem()),
 		}
 	case reflect.Ptr:
 		return &parsedPtr{
-			elem: reflectParseType(t.Elem()),
+			elem: r.reflectParseType(t.Elem()),
 		}
 	default:
 		// hopefully only builtins make it here; if we have to call `t.String()`, we don't get full package information
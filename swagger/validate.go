@@ -0,0 +1,193 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var rePathParam = regexp.MustCompile(`\{([^}]+)}`)
+
+// ValidationError describes a structural problem found in the swagger definition
+type ValidationError struct {
+	Location string
+	Message  string
+}
+
+func (e ValidationError) Error() string {
+	return e.Location + ": " + e.Message
+}
+
+// Validate checks the swagger definition for structural problems: path templates and path parameters that do not
+// match, duplicate operation ids, references to missing definitions, security requirements naming undefined security
+// definitions and endpoints without responses. It returns nil if no problems were found
+func (a *API) Validate() []ValidationError {
+	var errs []ValidationError
+	report := func(location, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	a.validateSecurity("security", a.Security, report)
+
+	operationIDs := map[string]string{}
+	for _, p := range sortedKeys(a.Paths) {
+		endpoints := a.Paths[p]
+		if endpoints == nil {
+			continue
+		}
+
+		template := map[string]bool{}
+		for _, match := range rePathParam.FindAllStringSubmatch(p, -1) {
+			template[match[1]] = true
+		}
+
+		endpoints.Walk(func(e *Endpoint) {
+			location := "paths." + p + "." + strings.ToLower(e.Method)
+
+			defined := map[string]bool{}
+			for i, param := range e.Parameters {
+				if param.In != "path" {
+					continue
+				}
+				defined[param.Name] = true
+
+				paramLocation := location + ".parameters[" + strconv.Itoa(i) + "]"
+				if !template[param.Name] {
+					report(paramLocation, "path parameter %v is not in the path template", param.Name)
+				}
+				if !param.Required {
+					report(paramLocation, "path parameter %v must be required", param.Name)
+				}
+			}
+			for _, match := range rePathParam.FindAllStringSubmatch(p, -1) {
+				if !defined[match[1]] {
+					report(location, "path parameter %v is not defined", match[1])
+				}
+			}
+
+			if e.OperationID != "" {
+				if other, ok := operationIDs[e.OperationID]; ok {
+					report(location, "operationId %v is already used by %v", e.OperationID, other)
+				} else {
+					operationIDs[e.OperationID] = location
+				}
+			}
+
+			if len(e.Responses) == 0 {
+				report(location, "no responses defined")
+			}
+
+			a.validateRefs(location, e, report)
+			a.validateSecurity(location+".security", e.Security, report)
+		})
+	}
+
+	for _, name := range sortedKeys(a.Definitions) {
+		a.validateRefs("definitions."+name, a.Definitions[name], report)
+	}
+
+	return errs
+}
+
+func (a *API) validateSecurity(location string, s *SecurityRequirement, report func(location, format string, args ...interface{})) {
+	if s == nil {
+		return
+	}
+
+	for _, requirement := range s.Requirements {
+		for _, name := range sortedKeys(requirement) {
+			if _, ok := a.SecurityDefinitions[name]; !ok {
+				report(location, "security definition %v is not defined", name)
+			}
+		}
+	}
+}
+
+// validateRefs reports every $ref found within v that does not resolve to a definition
+func (a *API) validateRefs(location string, v interface{}, report func(location, format string, args ...interface{})) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		report(location, "unable to encode: %v", err)
+		return
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		report(location, "unable to decode: %v", err)
+		return
+	}
+
+	walkRefs(location, doc, func(location, ref string) {
+		if !strings.HasPrefix(ref, "#/definitions/") {
+			return
+		}
+		name, err := url.QueryUnescape(strings.TrimPrefix(ref, "#/definitions/"))
+		if err != nil {
+			report(location, "invalid $ref %v", ref)
+			return
+		}
+		if _, ok := a.Definitions[name]; !ok {
+			report(location, "$ref %v refers to a missing definition", ref)
+		}
+	})
+}
+
+// namedMaps are the keywords whose values map names, e.g. of properties or response codes, rather than keywords to
+// schemas; within them, names like default or enum are not skipped
+var namedMaps = map[string]bool{"properties": true, "definitions": true, "responses": true, "headers": true}
+
+func walkRefs(location string, v interface{}, fn func(location, ref string)) {
+	walkNamedRefs(location, v, false, fn)
+}
+
+// walkNamedRefs calls fn for every $ref within v; named is true when the keys of v are names rather than keywords
+func walkNamedRefs(location string, v interface{}, named bool, fn func(location, ref string)) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(value) {
+			switch {
+			case named:
+				walkNamedRefs(location+"."+k, value[k], false, fn)
+			case k == "$ref":
+				if ref, ok := value[k].(string); ok {
+					fn(location, ref)
+				}
+			case k == "example" || k == "examples" || k == "x-example" || k == "default" || k == "enum":
+				// values, not schemas
+			default:
+				walkNamedRefs(location+"."+k, value[k], namedMaps[k], fn)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			walkNamedRefs(location+"["+strconv.Itoa(i)+"]", item, false, fn)
+		}
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, openAPIFixture(t).Validate())
+
+	api := swag.New(
+		swag.Security("missing_global"),
+		swag.SecurityScheme("basic", swagger.BasicSecurity()),
+		swag.Endpoints(
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.OperationID("getPet"),
+				endpoint.Path("id", "integer", "int64", "ID of pet to return"),
+				endpoint.Security("basic"),
+				endpoint.Security("missing"),
+			),
+			endpoint.New("delete", "/pet/{petId}", "Delete pet",
+				endpoint.OperationID("getPet"),
+				endpoint.Path("petId", "integer", "int64", "ID of pet to delete"),
+				endpoint.Response(http.StatusOK, Animal{}, "deleted"),
+			),
+		),
+	)
+	delete(api.Definitions, "Category")
+
+	assert.Equal(t, []swagger.ValidationError{
+		{Location: "security", Message: "security definition missing_global is not defined"},
+		{Location: "paths./pet/{petId}.get.parameters[0]", Message: "path parameter id is not in the path template"},
+		{Location: "paths./pet/{petId}.get", Message: "path parameter petId is not defined"},
+		{Location: "paths./pet/{petId}.get", Message: "operationId getPet is already used by paths./pet/{petId}.delete"},
+		{Location: "paths./pet/{petId}.get", Message: "no responses defined"},
+		{Location: "paths./pet/{petId}.get.security", Message: "security definition missing is not defined"},
+		{Location: "definitions.Animal.properties.category", Message: "$ref #/definitions/Category refers to a missing definition"},
+	}, api.Validate())
+}
+
+func TestValidateNamedRefs(t *testing.T) {
+	api := &swagger.API{
+		Swagger: "2.0",
+		Paths: map[string]*swagger.Endpoints{
+			"/pets": {Get: &swagger.Endpoint{
+				Path:   "/pets",
+				Method: "GET",
+				Responses: map[string]swagger.Response{
+					"default": {Description: "error", Schema: &swagger.Schema{Ref: "#/definitions/Error"}},
+				},
+			}},
+		},
+		Definitions: map[string]swagger.Object{
+			"Setting": {
+				Type: "object",
+				Properties: map[string]swagger.Property{
+					"default": {Ref: "#/definitions/Value"},
+					"enum":    {Type: "array", Items: &swagger.Items{Ref: "#/definitions/Value"}},
+				},
+				Example: map[string]interface{}{"$ref": "#/definitions/Example"},
+			},
+		},
+	}
+
+	assert.Equal(t, []swagger.ValidationError{
+		{Location: "paths./pets.get.responses.default.schema", Message: "$ref #/definitions/Error refers to a missing definition"},
+		{Location: "definitions.Setting.properties.default", Message: "$ref #/definitions/Value refers to a missing definition"},
+		{Location: "definitions.Setting.properties.enum.items", Message: "$ref #/definitions/Value refers to a missing definition"},
+	}, api.Validate())
+}
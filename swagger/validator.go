@@ -0,0 +1,234 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// validatorTags lists the go-playground/validator struct tags read for constraints; gin names its tag binding
+var validatorTags = []string{"validate", "binding"}
+
+// validatorFormats maps validator rules to the string formats they imply
+var validatorFormats = map[string]string{
+	"email": "email",
+	"url":   "uri",
+	"uri":   "uri",
+	"uuid":  "uuid",
+	"uuid3": "uuid",
+	"uuid4": "uuid",
+	"uuid5": "uuid",
+}
+
+// validatorRules returns the rules of the validator tags of a field, e.g. "required,min=1,dive,email"
+func validatorRules(tag reflect.StructTag) []string {
+	var rules []string
+	for _, name := range validatorTags {
+		if v := strings.TrimSpace(tag.Get(name)); v != "" && v != "-" {
+			rules = append(rules, strings.Split(v, ",")...)
+		}
+	}
+	return rules
+}
+
+// requiredRule reports whether the rules require the field itself; rules after dive apply to its elements
+func requiredRule(rules []string) bool {
+	for _, rule := range rules {
+		switch strings.TrimSpace(rule) {
+		case "required":
+			return true
+		case "dive":
+			return false
+		}
+	}
+	return false
+}
+
+// constrain translates validator rules into the constraints of the property. Constraints already set by the swagger
+// tags take precedence, rules after dive apply to the items of an array or the values of a map, and rules the
+// definition can't express, like alternatives or cross field comparisons, are ignored
+func constrain(p *Property, rules []string) {
+	keys := false
+	for i, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		switch {
+		case rule == "keys":
+			keys = true
+			continue
+		case rule == "endkeys":
+			keys = false
+			continue
+		case keys || strings.Contains(rule, "|"):
+			continue
+		case rule == "dive":
+			dive(p, rules[i+1:])
+			return
+		}
+
+		name, param := rule, ""
+		if n := strings.Index(rule, "="); n >= 0 {
+			name, param = rule[:n], rule[n+1:]
+		}
+		if format, ok := validatorFormats[name]; ok && param == "" {
+			if p.Type == "string" && p.Format == "" {
+				p.Format = format
+			}
+			continue
+		}
+		if param == "" || p.Ref != "" {
+			continue
+		}
+
+		if name == "oneof" {
+			if len(p.Enum) == 0 && (p.Type == "string" || p.Type == "integer" || p.Type == "number") {
+				for _, v := range oneOf(param) {
+					p.Enum = append(p.Enum, tagValue(*p, "oneof", v))
+				}
+			}
+			continue
+		}
+
+		switch p.Type {
+		case "string":
+			constrainLength(name, param, &p.MinLength, &p.MaxLength)
+		case "array":
+			constrainLength(name, param, &p.MinItems, &p.MaxItems)
+		case "integer", "number":
+			constrainNumber(name, param, p)
+		}
+	}
+}
+
+// dive applies the rules to the items of an array or the values of a map; nested arrays and maps are reached with
+// further dives
+func dive(p *Property, rules []string) {
+	if ap, ok := p.AdditionalProperties.(*Property); ok {
+		constrain(ap, rules)
+		return
+	}
+	if p.Items == nil {
+		return
+	}
+
+	items := Property{
+		Type:                 p.Items.Type,
+		Format:               p.Items.Format,
+		Enum:                 p.Items.Enum,
+		Ref:                  p.Items.Ref,
+		Items:                p.Items.Items,
+		MinItems:             p.Items.MinItems,
+		MaxItems:             p.Items.MaxItems,
+		MinLength:            p.Items.MinLength,
+		MaxLength:            p.Items.MaxLength,
+		Minimum:              p.Items.Minimum,
+		Maximum:              p.Items.Maximum,
+		ExclusiveMinimum:     p.Items.ExclusiveMinimum,
+		ExclusiveMaximum:     p.Items.ExclusiveMaximum,
+		MultipleOf:           p.Items.MultipleOf,
+		AdditionalProperties: p.Items.AdditionalProperties,
+	}
+	constrain(&items, rules)
+
+	p.Items.Format = items.Format
+	p.Items.Enum = items.Enum
+	p.Items.MinItems = items.MinItems
+	p.Items.MaxItems = items.MaxItems
+	p.Items.MinLength = items.MinLength
+	p.Items.MaxLength = items.MaxLength
+	p.Items.Minimum = items.Minimum
+	p.Items.Maximum = items.Maximum
+	p.Items.ExclusiveMinimum = items.ExclusiveMinimum
+	p.Items.ExclusiveMaximum = items.ExclusiveMaximum
+	p.Items.MultipleOf = items.MultipleOf
+}
+
+// constrainLength translates a rule into the minimum and maximum length of a string, or number of items of an array
+func constrainLength(name, param string, min, max *int) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+
+	switch name {
+	case "len":
+		if *min == 0 && *max == 0 {
+			*min, *max = n, n
+		}
+	case "min", "gte":
+		if *min == 0 {
+			*min = n
+		}
+	case "gt":
+		if *min == 0 {
+			*min = n + 1
+		}
+	case "max", "lte":
+		if *max == 0 {
+			*max = n
+		}
+	case "lt":
+		if *max == 0 && n > 0 {
+			*max = n - 1
+		}
+	}
+}
+
+// constrainNumber translates a rule into the bounds of a number
+func constrainNumber(name, param string, p *Property) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch name {
+	case "len", "eq":
+		if p.Minimum == nil && p.Maximum == nil {
+			min, max := n, n
+			p.Minimum, p.Maximum = &min, &max
+		}
+	case "min", "gte", "gt":
+		if p.Minimum == nil {
+			p.Minimum = &n
+			p.ExclusiveMinimum = name == "gt"
+		}
+	case "max", "lte", "lt":
+		if p.Maximum == nil {
+			p.Maximum = &n
+			p.ExclusiveMaximum = name == "lt"
+		}
+	}
+}
+
+// oneOf splits the values of a oneof rule; values are separated by spaces, and may be quoted with single quotes
+func oneOf(param string) []string {
+	var values []string
+	for param = strings.TrimSpace(param); param != ""; param = strings.TrimSpace(param) {
+		if param[0] == '\'' {
+			if n := strings.Index(param[1:], "'"); n >= 0 {
+				values = append(values, param[1:n+1])
+				param = param[n+2:]
+				continue
+			}
+		}
+		n := strings.Index(param, " ")
+		if n < 0 {
+			n = len(param)
+		}
+		values = append(values, param[:n])
+		param = param[n:]
+	}
+	return values
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Signup struct {
+	Email    string            `json:"email" binding:"required,email"`
+	ID       string            `json:"id" validate:"uuid4"`
+	Site     string            `json:"site" validate:"omitempty,url"`
+	Name     string            `json:"name" validate:"min=2,max=20"`
+	Code     string            `json:"code" validate:"len=6"`
+	Plan     string            `json:"plan" validate:"oneof=free pro 'pro plus'"`
+	Age      int               `json:"age" binding:"gte=18,lt=130"`
+	Score    *int64            `json:"score" validate:"gt=0"`
+	Tags     []string          `json:"tags" validate:"required,min=1,max=5,dive,min=2,oneof=a bb ccc"`
+	Levels   []int             `json:"levels" validate:"dive,min=1,max=9"`
+	Contacts map[string]string `json:"contacts" validate:"dive,keys,min=3,endkeys,email"`
+	Nick     string            `json:"nick" min_length:"4" validate:"min=1,email|url"`
+	Optional []string          `json:"optional" validate:"dive,required"`
+	Grid     [][]int           `json:"grid" validate:"dive,min=1,dive,max=9"`
+}
+
+func TestValidatorTags(t *testing.T) {
+	obj := defineObject(Signup{})
+	props := obj.Properties
+
+	assert.Equal(t, []string{"email", "tags"}, obj.Required)
+	assert.Equal(t, "email", props["email"].Format)
+	assert.Equal(t, "uuid", props["id"].Format)
+	assert.Equal(t, "uri", props["site"].Format)
+
+	assert.Equal(t, 2, props["name"].MinLength)
+	assert.Equal(t, 20, props["name"].MaxLength)
+	assert.Equal(t, 6, props["code"].MinLength)
+	assert.Equal(t, 6, props["code"].MaxLength)
+	assert.Equal(t, []interface{}{"free", "pro", "pro plus"}, props["plan"].Enum)
+
+	assert.Equal(t, float64(18), *props["age"].Minimum)
+	assert.False(t, props["age"].ExclusiveMinimum)
+	assert.Equal(t, float64(130), *props["age"].Maximum)
+	assert.True(t, props["age"].ExclusiveMaximum)
+	assert.Equal(t, float64(0), *props["score"].Minimum)
+	assert.True(t, props["score"].ExclusiveMinimum)
+
+	tags := props["tags"]
+	assert.Equal(t, 1, tags.MinItems)
+	assert.Equal(t, 5, tags.MaxItems)
+	assert.Equal(t, 2, tags.Items.MinLength)
+	assert.Equal(t, []interface{}{"a", "bb", "ccc"}, tags.Items.Enum)
+
+	levels := props["levels"]
+	assert.Equal(t, 0, levels.MinItems)
+	assert.Equal(t, float64(1), *levels.Items.Minimum)
+	assert.Equal(t, float64(9), *levels.Items.Maximum)
+
+	grid := props["grid"]
+	assert.Equal(t, 1, grid.Items.MinItems)
+	assert.Equal(t, float64(9), *grid.Items.Items.Maximum)
+
+	contacts := props["contacts"].AdditionalProperties.(*Property)
+	assert.Equal(t, "email", contacts.Format)
+	assert.Equal(t, 0, contacts.MinLength)
+
+	// swagger tags take precedence and alternatives are ignored
+	assert.Equal(t, 4, props["nick"].MinLength)
+	assert.Equal(t, "", props["nick"].Format)
+}
+
+func TestRequiredOnce(t *testing.T) {
+	type Login struct {
+		User     string `json:"user" required:"true" validate:"required"`
+		Password string `json:"password" required:"true" binding:"required,min=8"`
+		Remember bool   `json:"remember"`
+	}
+
+	assert.Equal(t, []string{"user", "password"}, defineObject(Login{}).Required)
+}
+
+func TestOneOf(t *testing.T) {
+	assert.Equal(t, []string{"a", "b c", "d"}, oneOf(" a 'b c'  d "))
+	assert.Equal(t, []string{"'unterminated"}, oneOf("'unterminated"))
+	assert.Nil(t, oneOf(""))
+}
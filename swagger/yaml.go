@@ -0,0 +1,181 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// topLevelOrder is the order in which the top level keys of a document are rendered as yaml; keys not listed are
+// rendered afterwards in their json order
+var topLevelOrder = []string{
+	"swagger",
+	"openapi",
+	"info",
+	"host",
+	"basePath",
+	"schemes",
+	"servers",
+	"consumes",
+	"produces",
+	"tags",
+	"paths",
+	"definitions",
+	"components",
+	"securityDefinitions",
+	"security",
+}
+
+// RenderYAML returns the static swagger schema, as yaml. Top level keys are emitted in a fixed, human friendly order
+// and all nested keys in a stable order, so that the output diffs cleanly
+func (a *API) RenderYAML() ([]byte, error) {
+	return renderYAML(a)
+}
+
+// renderYAML encodes v to yaml, preserving the key order of its json encoding except for the top level keys
+func renderYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	node, err := yamlNode(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if node.Kind == yaml.MappingNode {
+		sortTopLevel(node)
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlNode reads the next json value from decoder and converts it into a yaml node
+func yamlNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		kind, tag := yaml.MappingNode, "!!map"
+		if v == '[' {
+			kind, tag = yaml.SequenceNode, "!!seq"
+		}
+		node := &yaml.Node{Kind: kind, Tag: tag}
+		for decoder.More() {
+			if kind == yaml.MappingNode {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			child, err := yamlNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}, nil
+
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(string(v), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(v)}, nil
+
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}, nil
+
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected json token, %v", token)
+	}
+}
+
+// sortTopLevel reorders the key value pairs of a mapping node according to topLevelOrder
+func sortTopLevel(node *yaml.Node) {
+	rank := func(key string) int {
+		for i, k := range topLevelOrder {
+			if k == key {
+				return i
+			}
+		}
+		return len(topLevelOrder)
+	}
+
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return rank(pairs[i][0].Value) < rank(pairs[j][0].Value)
+	})
+
+	node.Content = node.Content[:0]
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+}
+
+// wantsYAML returns true if the request asks for yaml, either via the Accept header or a .yaml or .yml path
+func wantsYAML(req *http.Request) bool {
+	switch path.Ext(req.URL.Path) {
+	case ".yaml", ".yml":
+		return true
+	}
+
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package swagger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/miketonks/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderYAML(t *testing.T) {
+	api := openAPIFixture(t)
+	data, err := api.RenderYAML()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var keys []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" && line[0] != ' ' && line[0] != '-' {
+			keys = append(keys, line[:strings.Index(line, ":")])
+		}
+	}
+	assert.Equal(t, []string{"swagger", "info", "host", "basePath", "schemes", "paths", "definitions", "securityDefinitions"}, keys)
+	assert.Contains(t, string(data), `"200":`)
+
+	again, err := api.RenderYAML()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), string(again))
+
+	loaded, err := swagger.LoadYAML(data)
+	if !assert.Nil(t, err) {
+		return
+	}
+	expected, err := api.RenderJSON()
+	assert.Nil(t, err)
+	actual, err := loaded.RenderJSON()
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestHandlerYAML(t *testing.T) {
+	api := openAPIFixture(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	req.Header.Set("Accept", "text/html, application/yaml;q=0.9")
+	w := httptest.NewRecorder()
+	api.Handler(false)(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "swagger: \"2.0\"\n"))
+
+	w = httptest.NewRecorder()
+	api.VersionHandler(swagger.OpenAPI3, false)(w, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "openapi: 3.0.3\n"))
+
+	w = httptest.NewRecorder()
+	api.Handler(false)(w, httptest.NewRequest(http.MethodGet, "/swagger.json", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,148 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ui serves an embedded Swagger UI for a swagger definition; nothing is fetched from a CDN
+package ui
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	"path"
+	"strings"
+)
+
+//go:embed dist
+var dist embed.FS
+
+var index = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <title>{{.Title}}</title>
+    <link rel="stylesheet" type="text/css" href="./swagger-ui.css" />
+    <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32" />
+    <link rel="icon" type="image/png" href="./favicon-16x16.png" sizes="16x16" />
+    <style>
+      html { box-sizing: border-box; overflow-y: scroll; }
+      *, *:before, *:after { box-sizing: inherit; }
+      body { margin: 0; background: #fafafa; }
+    </style>
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="./swagger-ui-bundle.js" charset="UTF-8"></script>
+    <script>
+      window.onload = function() {
+        var config = {{.Config}};
+        config.dom_id = "#swagger-ui";
+        config.oauth2RedirectUrl = new URL("oauth2-redirect.html", window.location.href).href;
+        config.presets = [SwaggerUIBundle.presets.apis];
+        config.plugins = [SwaggerUIBundle.plugins.DownloadUrl];
+        window.ui = SwaggerUIBundle(config);
+      };
+    </script>
+  </body>
+</html>
+`))
+
+// config holds the Swagger UI configuration; see
+// https://github.com/swagger-api/swagger-ui/blob/master/docs/usage/configuration.md
+type config struct {
+	URL                  string `json:"url"`
+	DocExpansion         string `json:"docExpansion"`
+	DeepLinking          bool   `json:"deepLinking"`
+	PersistAuthorization bool   `json:"persistAuthorization"`
+}
+
+type options struct {
+	title  string
+	config config
+}
+
+// Option provides additional customizations to the ui Handler
+type Option func(o *options)
+
+// Title sets the title of the page; defaults to Swagger UI
+func Title(v string) Option {
+	return func(o *options) {
+		o.title = v
+	}
+}
+
+// DocExpansion controls the default expansion of operations and tags; one of list (the default), full or none
+func DocExpansion(v string) Option {
+	return func(o *options) {
+		o.config.DocExpansion = v
+	}
+}
+
+// DeepLinking enables deep linking of tags and operations; enabled by default
+func DeepLinking(v bool) Option {
+	return func(o *options) {
+		o.config.DeepLinking = v
+	}
+}
+
+// PersistAuthorization keeps authorization data across browser reloads; disabled by default
+func PersistAuthorization(v bool) Option {
+	return func(o *options) {
+		o.config.PersistAuthorization = v
+	}
+}
+
+// Handler returns an http.Handler serving a Swagger UI page for the definition at specURL, e.g. the path the
+// API.Handler is mounted on, together with its assets. The handler may be mounted under any prefix, e.g. /docs/, as
+// the page refers to its assets relative to its own location
+func Handler(specURL string, opts ...Option) http.Handler {
+	o := options{
+		title: "Swagger UI",
+		config: config{
+			URL:          specURL,
+			DocExpansion: "list",
+			DeepLinking:  true,
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	assets := http.FileServer(http.FS(dist))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name := path.Base(req.URL.Path)
+		if name != "index.html" && !strings.HasSuffix(req.URL.Path, "/") {
+			if _, err := dist.Open("dist/" + name); err == nil {
+				asset := req.Clone(req.Context())
+				asset.URL.Path = "/dist/" + name
+				assets.ServeHTTP(w, asset)
+			} else if path.Ext(name) == "" {
+				// the page refers to its assets relatively, so it must be served from a directory
+				http.Redirect(w, req, req.URL.Path+"/", http.StatusMovedPermanently)
+			} else {
+				http.NotFound(w, req)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		index.Execute(w, struct {
+			Title  string
+			Config config
+		}{
+			Title:  o.title,
+			Config: o.config,
+		})
+	})
+}
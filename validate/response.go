@@ -0,0 +1,173 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miketonks/swag/swagger"
+)
+
+// ReportFunc receives the error describing how a response written for req differs from the definition of e
+type ReportFunc func(e *swagger.Endpoint, req *http.Request, err error)
+
+// TestingT is the subset of testing.TB used by FailTest
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// FailTest returns a ReportFunc that fails the test for every response that does not match its definition
+func FailTest(t TestingT) ReportFunc {
+	return func(e *swagger.Endpoint, req *http.Request, err error) {
+		t.Errorf("%v %v: %v", req.Method, req.URL.Path, err)
+	}
+}
+
+// Response validates a response written for the specified endpoint: the status code must be declared, declared
+// headers must be present and json bodies must match the schema, without undeclared properties. The returned error,
+// if any, is an *Error
+func (v *Validator) Response(e *swagger.Endpoint, status int, header http.Header, body []byte) error {
+	var violations []Violation
+	reporter := func(in string) func(field, message string) {
+		return func(field, message string) {
+			violations = append(violations, Violation{In: in, Field: field, Message: message})
+		}
+	}
+
+	response, ok := e.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = e.Responses["default"]
+	}
+	if !ok {
+		reporter("status")("", "status code "+strconv.Itoa(status)+" is not declared")
+		return responseError(violations)
+	}
+
+	names := make([]string, 0, len(response.Headers))
+	for name := range response.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		h := response.Headers[name]
+		report := reporter("header")
+		values := header.Values(name)
+		if len(values) == 0 {
+			report(name, "is declared but missing")
+			continue
+		}
+
+		value, ok := coerce(h.Type, values[0])
+		if !ok {
+			report(name, "must be "+article(h.Type))
+			continue
+		}
+		v.strict.check(toSchema(h), value, name, report)
+	}
+
+	if response.Schema != nil && response.Schema.Type != "file" && len(bytes.TrimSpace(body)) > 0 {
+		mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+		if err != nil || strings.Contains(mediaType, "json") {
+			report := reporter("body")
+
+			var value interface{}
+			if err := json.Unmarshal(body, &value); err != nil {
+				report("", "must be valid json: "+err.Error())
+			} else {
+				v.strict.check(toSchema(response.Schema), value, "", report)
+			}
+		}
+	}
+
+	return responseError(violations)
+}
+
+// WrapResponses returns an http.Handler that passes every response written by next through to the client and reports
+// those that do not match the definition of e
+func (v *Validator) WrapResponses(e *swagger.Endpoint, next http.Handler, report ReportFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, req)
+
+		rec.sent(http.StatusOK)
+		if err := v.Response(e, rec.status, rec.header, rec.body.Bytes()); err != nil {
+			report(e, req, err)
+		}
+	})
+}
+
+// WrapHandlers replaces the handler of every endpoint of the api with one that reports responses which do not match
+// the definition; call it before binding the endpoints with Walk. Only http.Handler and func(http.ResponseWriter,
+// *http.Request) handlers can be wrapped; the endpoints with other handlers, e.g. gin or echo handlers, are returned
+// so they can be reported, as their responses go unchecked
+func (v *Validator) WrapHandlers(report ReportFunc) []*swagger.Endpoint {
+	var unwrapped []*swagger.Endpoint
+	v.api.Walk(func(_ string, e *swagger.Endpoint) {
+		switch h := e.Handler.(type) {
+		case http.Handler:
+			e.Handler = v.WrapResponses(e, h, report)
+		case func(http.ResponseWriter, *http.Request):
+			e.Handler = v.WrapResponses(e, http.HandlerFunc(h), report)
+		default:
+			unwrapped = append(unwrapped, e)
+		}
+	})
+	return unwrapped
+}
+
+func responseError(violations []Violation) error {
+	if violations == nil {
+		return nil
+	}
+
+	return &Error{
+		Message:    "response does not match the api definition",
+		Violations: violations,
+	}
+}
+
+// recorder captures the status code, headers and body written to the underlying http.ResponseWriter
+type recorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+// sent snapshots the headers as written by the handler, before the underlying writer sniffs a content type
+func (r *recorder) sent(code int) {
+	if r.status == 0 {
+		r.status = code
+		r.header = r.Header().Clone()
+	}
+}
+
+func (r *recorder) WriteHeader(code int) {
+	r.sent(code)
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.sent(http.StatusOK)
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package validate_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/miketonks/swag/validate"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func responseAPI(handler http.HandlerFunc) *swagger.API {
+	swagger.UsePackageName = false
+
+	return swag.New(
+		swag.Endpoints(
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.Handler(handler),
+				endpoint.Path("petId", "integer", "int64", "ID of pet to return"),
+				endpoint.Response(http.StatusOK, Category{}, "successful operation",
+					endpoint.Header("X-Rate-Limit", "integer", "int32", "calls per hour allowed by the user"),
+				),
+				endpoint.Response(http.StatusNotFound, Category{}, "not found"),
+			),
+		),
+	)
+}
+
+func TestResponse(t *testing.T) {
+	api := responseAPI(nil)
+	e := api.Paths["/pet/{petId}"].Get
+	v := validate.New(api)
+
+	header := http.Header{}
+	header.Set("X-Rate-Limit", "10")
+	header.Set("Content-Type", "application/json")
+	assert.Nil(t, v.Response(e, http.StatusOK, header, []byte(`{"id":1,"name":"dogs"}`)))
+
+	header.Set("X-Rate-Limit", "lots")
+	err := v.Response(e, http.StatusOK, header, []byte(`{"id":"1","name":"dogs","extra":true}`))
+	assert.Equal(t, []validate.Violation{
+		{In: "header", Field: "X-Rate-Limit", Message: "must be an integer"},
+		{In: "body", Field: "extra", Message: "is not allowed"},
+		{In: "body", Field: "id", Message: "must be an integer"},
+	}, violations(err))
+
+	err = v.Response(e, http.StatusOK, http.Header{}, nil)
+	assert.Equal(t, []validate.Violation{
+		{In: "header", Field: "X-Rate-Limit", Message: "is declared but missing"},
+	}, violations(err))
+
+	assert.Nil(t, v.Response(e, http.StatusNotFound, http.Header{}, nil))
+
+	err = v.Response(e, http.StatusTeapot, http.Header{}, nil)
+	assert.Equal(t, []validate.Violation{
+		{In: "status", Message: "status code 418 is not declared"},
+	}, violations(err))
+}
+
+func TestWrapHandlers(t *testing.T) {
+	api := responseAPI(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Rate-Limit", "10")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "name": "dogs", "color": "brown"})
+	})
+
+	ft := &fakeT{}
+	assert.Empty(t, validate.New(api).WrapHandlers(validate.FailTest(ft)))
+
+	w := httptest.NewRecorder()
+	api.Paths["/pet/{petId}"].ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pet/1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":1,"name":"dogs","color":"brown"}`, w.Body.String(), "expected response to be passed through")
+	assert.Equal(t, []string{
+		"GET /pet/1: response does not match the api definition: body color: is not allowed",
+	}, ft.errors)
+}
+
+func TestWrapHandlersUnsupported(t *testing.T) {
+	api := swag.New(
+		swag.Endpoints(
+			endpoint.New("get", "/pets", "List pets", endpoint.Handler(func(w http.ResponseWriter, req *http.Request) {})),
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID", endpoint.Handler(func(c interface{}) {})),
+		),
+	)
+
+	unwrapped := validate.New(api).WrapHandlers(validate.FailTest(&fakeT{}))
+	assert.Len(t, unwrapped, 1)
+	assert.Equal(t, "/pet/{petId}", unwrapped[0].Path)
+
+	_, ok := api.Paths["/pets"].Get.Handler.(http.Handler)
+	assert.True(t, ok, "expected plain handler funcs to be wrapped")
+}
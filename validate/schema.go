@@ -0,0 +1,385 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// schema is the generic, decoded json form of a swagger schema, property, items or parameter
+type schema map[string]interface{}
+
+// toSchema converts any of the swagger types into its generic json form
+func toSchema(v interface{}) schema {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	s := schema{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	return s
+}
+
+func (s schema) str(key string) string {
+	v, _ := s[key].(string)
+	return v
+}
+
+func (s schema) boolean(key string) bool {
+	v, _ := s[key].(bool)
+	return v
+}
+
+func (s schema) number(key string) (float64, bool) {
+	v, ok := s[key].(float64)
+	return v, ok
+}
+
+func (s schema) child(key string) schema {
+	v, _ := s[key].(map[string]interface{})
+	return v
+}
+
+// checker validates decoded json values against generic schemas, resolving references against definitions
+type checker struct {
+	definitions     map[string]schema
+	disallowUnknown bool
+
+	mux      sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func newChecker(definitions interface{}, disallowUnknown bool) *checker {
+	c := &checker{
+		definitions:     map[string]schema{},
+		disallowUnknown: disallowUnknown,
+		patterns:        map[string]*regexp.Regexp{},
+	}
+
+	for name, def := range toSchema(definitions) {
+		if m, ok := def.(map[string]interface{}); ok {
+			c.definitions[name] = m
+		}
+	}
+
+	return c
+}
+
+func (c *checker) resolve(s schema) schema {
+	s, _ = c.resolveName(s)
+	return s
+}
+
+// resolveName follows the references of s and returns the schema found with the name of the last definition referred
+// to, if any
+func (c *checker) resolveName(s schema) (schema, string) {
+	var name string
+	for i := 0; s != nil && i < 32; i++ {
+		ref := s.str("$ref")
+		if ref == "" {
+			return s, name
+		}
+
+		var err error
+		name, err = url.QueryUnescape(strings.TrimPrefix(ref, "#/definitions/"))
+		if err != nil {
+			return nil, ""
+		}
+		s = c.definitions[name]
+	}
+	return s, name
+}
+
+// expand replaces a schema with a discriminator by the definition named by the value's discriminator property, then
+// merges the parts of an allOf schema into a single schema. The definition must be listed in the enum of the
+// discriminator property or refer to the base definition in its allOf; other names are reported, unless the enum
+// already reports them
+func (c *checker) expand(s schema, base string, value interface{}, field string, report func(field, message string)) schema {
+	if name := s.str("discriminator"); name != "" {
+		if v, ok := value.(map[string]interface{}); ok {
+			if sub, ok := v[name].(string); ok && sub != base {
+				enum, _ := s.child("properties").child(name)["enum"].([]interface{})
+				switch {
+				case c.definitions[sub] != nil && (contains(enum, sub) || c.extends(sub, base)):
+					s = c.definitions[sub]
+				case len(enum) == 0 && base != "":
+					report(join(field, name), fmt.Sprintf("must name a subtype of %v", base))
+				case len(enum) == 0:
+					report(join(field, name), "must name a subtype")
+				}
+			}
+		}
+	}
+	return c.merge(s, 0)
+}
+
+// extends reports whether the definition named sub refers to the base definition in its allOf
+func (c *checker) extends(sub, base string) bool {
+	if base == "" {
+		return false
+	}
+
+	parts, _ := c.definitions[sub]["allOf"].([]interface{})
+	for _, part := range parts {
+		if m, ok := part.(map[string]interface{}); ok {
+			if ref := schema(m).str("$ref"); ref != "" {
+				if name, err := url.QueryUnescape(strings.TrimPrefix(ref, "#/definitions/")); err == nil && name == base {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// merge combines the properties and required fields of the allOf parts with those of s; other keywords are taken
+// from s only
+func (c *checker) merge(s schema, depth int) schema {
+	parts, ok := s["allOf"].([]interface{})
+	if !ok || depth > 32 {
+		return s
+	}
+
+	merged := schema{}
+	properties := map[string]interface{}{}
+	var required []interface{}
+	add := func(part schema) {
+		for name, p := range part.child("properties") {
+			properties[name] = p
+		}
+		if r, ok := part["required"].([]interface{}); ok {
+			required = append(required, r...)
+		}
+	}
+
+	for k, v := range s {
+		if k != "allOf" {
+			merged[k] = v
+		}
+	}
+	for _, part := range parts {
+		if m, ok := part.(map[string]interface{}); ok {
+			add(c.merge(c.resolve(m), depth+1))
+		}
+	}
+	add(s)
+
+	merged["properties"] = properties
+	merged["required"] = required
+	return merged
+}
+
+func (c *checker) pattern(expr string) *regexp.Regexp {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	re, ok := c.patterns[expr]
+	if !ok {
+		// an invalid pattern in the definition is not the fault of the request
+		re, _ = regexp.Compile(expr)
+		c.patterns[expr] = re
+	}
+	return re
+}
+
+// check validates value against s, reporting every violation found to report
+func (c *checker) check(s schema, value interface{}, field string, report func(field, message string)) {
+	nullable := s.boolean("x-nullable")
+	s, base := c.resolveName(s)
+	s = c.expand(s, base, value, field, report)
+	if s == nil {
+		return
+	}
+
+	if value == nil {
+		if s.str("type") != "" && !nullable && !s.boolean("x-nullable") {
+			report(field, "must not be null")
+		}
+		return
+	}
+
+	switch s.str("type") {
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			report(field, "must be an integer")
+			return
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			report(field, "must be a number")
+			return
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			report(field, "must be a string")
+			return
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report(field, "must be a boolean")
+			return
+		}
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			report(field, "must be an array")
+			return
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			report(field, "must be an object")
+			return
+		}
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report(field, fmt.Sprintf("must be one of %v", enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if min, ok := s.number("minLength"); ok && float64(length) < min {
+			report(field, fmt.Sprintf("must be at least %v characters long", min))
+		}
+		if max, ok := s.number("maxLength"); ok && float64(length) > max {
+			report(field, fmt.Sprintf("must be at most %v characters long", max))
+		}
+		if expr := s.str("pattern"); expr != "" {
+			if re := c.pattern(expr); re != nil && !re.MatchString(v) {
+				report(field, fmt.Sprintf("must match pattern %v", expr))
+			}
+		}
+
+	case float64:
+		if min, ok := s.number("minimum"); ok {
+			if s.boolean("exclusiveMinimum") && v <= min {
+				report(field, fmt.Sprintf("must be greater than %v", min))
+			} else if v < min {
+				report(field, fmt.Sprintf("must be greater than or equal to %v", min))
+			}
+		}
+		if max, ok := s.number("maximum"); ok {
+			if s.boolean("exclusiveMaximum") && v >= max {
+				report(field, fmt.Sprintf("must be less than %v", max))
+			} else if v > max {
+				report(field, fmt.Sprintf("must be less than or equal to %v", max))
+			}
+		}
+		// allow for the rounding of decimal multiples, like 0.3 of 0.1
+		if m, ok := s.number("multipleOf"); ok && m > 0 {
+			if q := v / m; math.Abs(q-math.Round(q)) > 1e-9 {
+				report(field, fmt.Sprintf("must be a multiple of %v", m))
+			}
+		}
+
+	case []interface{}:
+		if min, ok := s.number("minItems"); ok && float64(len(v)) < min {
+			report(field, fmt.Sprintf("must contain at least %v items", min))
+		}
+		if max, ok := s.number("maxItems"); ok && float64(len(v)) > max {
+			report(field, fmt.Sprintf("must contain at most %v items", max))
+		}
+		if s.boolean("uniqueItems") && hasDuplicates(v) {
+			report(field, "must contain unique items")
+		}
+		if items := s.child("items"); items != nil {
+			for i, item := range v {
+				c.check(items, item, field+"["+strconv.Itoa(i)+"]", report)
+			}
+		}
+
+	case map[string]interface{}:
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[fmt.Sprint(name)]; !ok {
+					report(join(field, fmt.Sprint(name)), "is required")
+				}
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		properties := s.child("properties")
+		for _, name := range names {
+			item := v[name]
+			if p, ok := properties[name].(map[string]interface{}); ok {
+				c.check(p, item, join(field, name), report)
+				continue
+			}
+
+			switch ap := s["additionalProperties"].(type) {
+			case map[string]interface{}:
+				c.check(ap, item, join(field, name), report)
+			case bool:
+				if !ap && c.disallowUnknown && properties != nil {
+					report(join(field, name), "is not allowed")
+				}
+			}
+		}
+	}
+}
+
+func hasDuplicates(items []interface{}) bool {
+	for i := 0; i < len(items); i++ {
+		for j := i + 1; j < len(items); j++ {
+			if reflect.DeepEqual(items[i], items[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(items []interface{}, v string) bool {
+	for _, item := range items {
+		if fmt.Sprint(item) == v {
+			return true
+		}
+	}
+	return false
+}
+
+func join(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
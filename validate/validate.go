@@ -0,0 +1,402 @@
+// Package validate checks incoming requests against the swagger definition they were documented with
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miketonks/swag/swagger"
+)
+
+// maxMemory is the amount of a multipart form held in memory while validating form parameters
+const maxMemory = 32 << 20
+
+// Violation describes a single way in which a request does not match its endpoint definition
+type Violation struct {
+	In      string `json:"in"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.In + ": " + v.Message
+	}
+	return v.In + " " + v.Field + ": " + v.Message
+}
+
+// Error is returned when a request does not match its endpoint definition; it lists every violation found
+type Error struct {
+	Message    string      `json:"message"`
+	Violations []Violation `json:"violations"`
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.String())
+	}
+	return e.Message + ": " + strings.Join(messages, "; ")
+}
+
+// ErrorHandlerFunc writes the response for a request that failed validation
+type ErrorHandlerFunc func(w http.ResponseWriter, req *http.Request, err error)
+
+// Validator checks requests, and optionally responses, against the endpoints of a swagger definition
+type Validator struct {
+	api          *swagger.API
+	routes       []route
+	checker      *checker
+	strict       *checker
+	errorHandler ErrorHandlerFunc
+
+	disallowUnknown bool
+}
+
+// Option provides configuration options to the Validator
+type Option func(v *Validator)
+
+// DisallowUnknownFields rejects body properties that are not part of a definition which does not allow additional
+// properties
+func DisallowUnknownFields() Option {
+	return func(v *Validator) {
+		v.disallowUnknown = true
+	}
+}
+
+// ErrorHandler overrides how requests that fail validation are answered; by default a 400 with a json encoded Error
+// is returned
+func ErrorHandler(fn ErrorHandlerFunc) Option {
+	return func(v *Validator) {
+		v.errorHandler = fn
+	}
+}
+
+// New constructs a Validator for the endpoints of the api; the api should not be modified once the Validator is in use
+func New(api *swagger.API, options ...Option) *Validator {
+	v := &Validator{
+		api:          api,
+		errorHandler: WriteError,
+	}
+
+	for _, opt := range options {
+		opt(v)
+	}
+
+	v.checker = newChecker(api.Definitions, v.disallowUnknown)
+	v.strict = newChecker(api.Definitions, true)
+	for rawPath, endpoints := range api.Paths {
+		v.routes = append(v.routes, newRoute(path.Join(api.BasePath, rawPath), endpoints))
+	}
+	sort.SliceStable(v.routes, func(i, j int) bool {
+		if len(v.routes[i].names) != len(v.routes[j].names) {
+			return len(v.routes[i].names) < len(v.routes[j].names)
+		}
+		return v.routes[i].literal > v.routes[j].literal
+	})
+
+	return v
+}
+
+// Middleware returns an http.Handler that validates each request before passing it on to next
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := v.Check(req); err != nil {
+			v.Reject(w, req, err)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// Reject answers a request that failed validation using the configured ErrorHandler
+func (v *Validator) Reject(w http.ResponseWriter, req *http.Request, err error) {
+	v.errorHandler(w, req, err)
+}
+
+// Match finds the endpoint documenting the request along with the values of its path parameters
+func (v *Validator) Match(req *http.Request) (*swagger.Endpoint, map[string]string, bool) {
+	for _, r := range v.routes {
+		matches := r.re.FindStringSubmatch(req.URL.Path)
+		if matches == nil {
+			continue
+		}
+
+		e := find(r.endpoints, req.Method)
+		if e == nil {
+			continue
+		}
+
+		params := map[string]string{}
+		for i, name := range r.names {
+			params[name] = matches[i+1]
+		}
+		return e, params, true
+	}
+
+	return nil, nil, false
+}
+
+// Check validates the request against its matching endpoint; requests that do not match any endpoint are not
+// validated. The returned error, if any, is an *Error
+func (v *Validator) Check(req *http.Request) error {
+	e, params, ok := v.Match(req)
+	if !ok {
+		return nil
+	}
+	return v.Request(e, req, params)
+}
+
+// Request validates the request against the specified endpoint; pathParams holds the values of the path parameters.
+// The returned error, if any, is an *Error
+func (v *Validator) Request(e *swagger.Endpoint, req *http.Request, pathParams map[string]string) error {
+	var violations []Violation
+
+	for _, p := range e.Parameters {
+		in := p.In
+		report := func(field, message string) {
+			violations = append(violations, Violation{In: in, Field: field, Message: message})
+		}
+
+		switch p.In {
+		case "path":
+			var values []string
+			if value, ok := pathParams[p.Name]; ok {
+				values = []string{value}
+			}
+			v.checkParameter(p, values, report)
+
+		case "query":
+			v.checkParameter(p, req.URL.Query()[p.Name], report)
+
+		case "header":
+			v.checkParameter(p, req.Header.Values(p.Name), report)
+
+		case "formData":
+			if err := parseForm(req); err != nil {
+				report("", "unable to parse form: "+err.Error())
+				continue
+			}
+			if p.Type == "file" {
+				if p.Required && (req.MultipartForm == nil || len(req.MultipartForm.File[p.Name]) == 0) {
+					report(p.Name, "is required")
+				}
+				continue
+			}
+			v.checkParameter(p, req.PostForm[p.Name], report)
+
+		case "body":
+			v.checkBody(p, req, report)
+		}
+	}
+
+	if violations == nil {
+		return nil
+	}
+
+	return &Error{
+		Message:    "request does not match the api definition",
+		Violations: violations,
+	}
+}
+
+// WriteError is the default ErrorHandler; it writes err as json with a 400 status code
+func WriteError(w http.ResponseWriter, _ *http.Request, err error) {
+	body, ok := err.(*Error)
+	if !ok {
+		body = &Error{Message: err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (v *Validator) checkParameter(p swagger.Parameter, values []string, report func(field, message string)) {
+	if len(values) == 0 {
+		if p.Required {
+			report(p.Name, "is required")
+		}
+		return
+	}
+	if p.AllowEmptyValue && len(values) == 1 && values[0] == "" {
+		return
+	}
+
+	s := toSchema(p)
+	if p.Type != "array" {
+		value, ok := coerce(p.Type, values[0])
+		if !ok {
+			report(p.Name, "must be "+article(p.Type))
+			return
+		}
+		v.checker.check(s, value, p.Name, report)
+		return
+	}
+
+	if s.str("collectionFormat") != "multi" {
+		values = split(s.str("collectionFormat"), values[0])
+	}
+
+	itemType := ""
+	if p.Items != nil {
+		itemType = p.Items.Type
+	}
+
+	items := make([]interface{}, 0, len(values))
+	for i, raw := range values {
+		value, ok := coerce(itemType, raw)
+		if !ok {
+			report(p.Name+"["+strconv.Itoa(i)+"]", "must be "+article(itemType))
+			return
+		}
+		items = append(items, value)
+	}
+	v.checker.check(s, items, p.Name, report)
+}
+
+func (v *Validator) checkBody(p swagger.Parameter, req *http.Request, report func(field, message string)) {
+	var data []byte
+	if req.Body != nil {
+		var err error
+		data, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		if err != nil {
+			report("", "unable to read body: "+err.Error())
+			return
+		}
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		if p.Required {
+			report("", "is required")
+		}
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil && !strings.Contains(mediaType, "json") {
+		return
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		report("", "must be valid json: "+err.Error())
+		return
+	}
+
+	if p.Schema != nil {
+		v.checker.check(toSchema(p.Schema), value, "", report)
+	}
+}
+
+func parseForm(req *http.Request) error {
+	if req.PostForm != nil {
+		return nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		return req.ParseMultipartForm(maxMemory)
+	}
+	return req.ParseForm()
+}
+
+// coerce converts a raw parameter value into the json value matching typ
+func coerce(typ, raw string) (interface{}, bool) {
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		return float64(n), err == nil
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		return n, err == nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		return b, err == nil
+	default:
+		return raw, true
+	}
+}
+
+func split(collectionFormat, raw string) []string {
+	switch collectionFormat {
+	case "ssv":
+		return strings.Split(raw, " ")
+	case "tsv":
+		return strings.Split(raw, "\t")
+	case "pipes":
+		return strings.Split(raw, "|")
+	default:
+		return strings.Split(raw, ",")
+	}
+}
+
+func article(typ string) string {
+	switch typ {
+	case "integer", "array", "object":
+		return "an " + typ
+	default:
+		return "a " + typ
+	}
+}
+
+// route matches request paths against a swagger path template such as /pet/{petId}
+type route struct {
+	re        *regexp.Regexp
+	names     []string
+	literal   int
+	endpoints *swagger.Endpoints
+}
+
+var rePathParam = regexp.MustCompile(`\{([^}]+)}`)
+
+func newRoute(template string, endpoints *swagger.Endpoints) route {
+	r := route{endpoints: endpoints}
+
+	expr := "^"
+	last := 0
+	for _, loc := range rePathParam.FindAllStringSubmatchIndex(template, -1) {
+		literal := template[last:loc[0]]
+		expr += regexp.QuoteMeta(literal) + "([^/]+)"
+		r.literal += len(literal)
+		r.names = append(r.names, template[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	expr += regexp.QuoteMeta(template[last:]) + "/?$"
+	r.literal += len(template) - last
+
+	r.re = regexp.MustCompile(expr)
+	return r
+}
+
+func find(endpoints *swagger.Endpoints, method string) *swagger.Endpoint {
+	var found *swagger.Endpoint
+	endpoints.Walk(func(e *swagger.Endpoint) {
+		if strings.EqualFold(e.Method, method) {
+			found = e
+		}
+	})
+	return found
+}
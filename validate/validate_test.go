@@ -0,0 +1,299 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package validate_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/miketonks/swag"
+	"github.com/miketonks/swag/endpoint"
+	"github.com/miketonks/swag/swagger"
+	"github.com/miketonks/swag/validate"
+	"github.com/stretchr/testify/assert"
+)
+
+type Category struct {
+	ID   int64  `json:"id" minimum:"1"`
+	Name string `json:"name" min_length:"2"`
+}
+
+type Pet struct {
+	Name     string    `json:"name" required:"true" pattern:"^[a-z]+$"`
+	Status   string    `json:"status" enum:"available,sold"`
+	Tags     []string  `json:"tags" max_items:"2"`
+	Category *Category `json:"category"`
+	Price    float64   `json:"price" minimum:"0.5" multiple_of:"0.25"`
+}
+
+func testAPI() *swagger.API {
+	swagger.UsePackageName = false
+
+	return swag.New(
+		swag.BasePath("/api"),
+		swag.Endpoints(
+			endpoint.New("post", "/pet", "Add a new pet to the store",
+				endpoint.Body(Pet{}, "Pet object that needs to be added to the store", true),
+			),
+			endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+				endpoint.Path("petId", "integer", "int64", "ID of pet to return"),
+				endpoint.RequestHeader("X-Request-ID", "string", "", "request id", true),
+				endpoint.QueryList([]swagger.Parameter{
+					{Name: "status", Type: "string", Enum: []interface{}{"available", "sold"}},
+					{Name: "limit", Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(100)},
+					{Name: "tags", Type: "array", Items: &swagger.Items{Type: "integer"}},
+					{Name: "sort", Type: "string", Enum: []interface{}{"asc", "desc"}, AllowEmptyValue: true},
+				}),
+			),
+			endpoint.New("get", "/pet/findByStatus", "Finds pets by status"),
+			endpoint.New("post", "/pet/{petId}/form", "Updates a pet with form data",
+				endpoint.Path("petId", "integer", "int64", "ID of pet to update"),
+				endpoint.FormData("name", "string", "", "name of the pet", true),
+				endpoint.FormData("age", "integer", "", "age of the pet", false),
+			),
+		),
+	)
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func TestMatch(t *testing.T) {
+	v := validate.New(testAPI())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pet/findByStatus", nil)
+	e, params, ok := v.Match(req)
+	assert.True(t, ok)
+	assert.Equal(t, "/pet/findByStatus", e.Path)
+	assert.Empty(t, params)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/pet/123", nil)
+	e, params, ok = v.Match(req)
+	assert.True(t, ok)
+	assert.Equal(t, "/pet/{petId}", e.Path)
+	assert.Equal(t, map[string]string{"petId": "123"}, params)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/pet/123", nil)
+	_, _, ok = v.Match(req)
+	assert.False(t, ok)
+
+	req = httptest.NewRequest(http.MethodGet, "/pet/123", nil)
+	_, _, ok = v.Match(req)
+	assert.False(t, ok)
+}
+
+func violations(err error) []validate.Violation {
+	if err == nil {
+		return nil
+	}
+	return err.(*validate.Error).Violations
+}
+
+func TestCheckParameters(t *testing.T) {
+	v := validate.New(testAPI())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pet/123?status=sold&limit=10&tags=1,2", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	assert.Nil(t, v.Check(req))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/pet/123?sort=", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	assert.Nil(t, v.Check(req))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/pet/abc?status=lost&limit=1000&tags=1,b&sort=up", nil)
+	assert.Equal(t, []validate.Violation{
+		{In: "path", Field: "petId", Message: "must be an integer"},
+		{In: "header", Field: "X-Request-ID", Message: "is required"},
+		{In: "query", Field: "status", Message: "must be one of [available sold]"},
+		{In: "query", Field: "limit", Message: "must be less than or equal to 100"},
+		{In: "query", Field: "tags[1]", Message: "must be an integer"},
+		{In: "query", Field: "sort", Message: "must be one of [asc desc]"},
+	}, violations(v.Check(req)))
+}
+
+func TestCheckBody(t *testing.T) {
+	v := validate.New(testAPI())
+
+	body := `{"name":"rex","status":"sold","tags":["a"],"category":{"id":1,"name":"dogs"},"price":0.75}`
+	req := httptest.NewRequest(http.MethodPost, "/api/pet", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	assert.Nil(t, v.Check(req))
+
+	data, err := io.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, body, string(data), "expected body to be readable after validation")
+
+	body = `{"name":"Rex","status":"lost","tags":["a","b","c"],"category":{"id":0,"name":"d"},"price":0.3,"extra":true}`
+	req = httptest.NewRequest(http.MethodPost, "/api/pet", strings.NewReader(body))
+	assert.Equal(t, []validate.Violation{
+		{In: "body", Field: "category.id", Message: "must be greater than or equal to 1"},
+		{In: "body", Field: "category.name", Message: "must be at least 2 characters long"},
+		{In: "body", Field: "name", Message: "must match pattern ^[a-z]+$"},
+		{In: "body", Field: "price", Message: "must be greater than or equal to 0.5"},
+		{In: "body", Field: "price", Message: "must be a multiple of 0.25"},
+		{In: "body", Field: "status", Message: "must be one of [available sold]"},
+		{In: "body", Field: "tags", Message: "must contain at most 2 items"},
+	}, violations(v.Check(req)))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/pet", strings.NewReader(`{"status":"sold","category":null}`))
+	assert.Equal(t, []validate.Violation{
+		{In: "body", Field: "name", Message: "is required"},
+	}, violations(v.Check(req)))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/pet", nil)
+	assert.Equal(t, []validate.Violation{
+		{In: "body", Message: "is required"},
+	}, violations(v.Check(req)))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/pet", strings.NewReader(`{"name":`))
+	assert.Len(t, violations(v.Check(req)), 1)
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	v := validate.New(testAPI(), validate.DisallowUnknownFields())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/pet", strings.NewReader(`{"name":"rex","extra":true}`))
+	assert.Equal(t, []validate.Violation{
+		{In: "body", Field: "extra", Message: "is not allowed"},
+	}, violations(v.Check(req)))
+}
+
+func TestCheckForm(t *testing.T) {
+	v := validate.New(testAPI())
+
+	form := url.Values{"age": {"old"}}
+	req := httptest.NewRequest(http.MethodPost, "/api/pet/1/form", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Equal(t, []validate.Violation{
+		{In: "formData", Field: "name", Message: "is required"},
+		{In: "formData", Field: "age", Message: "must be an integer"},
+	}, violations(v.Check(req)))
+}
+
+func TestMiddleware(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		called = true
+	})
+	h := validate.New(testAPI()).Middleware(next)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/pet/abc", nil))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	body := validate.Error{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Len(t, body.Violations, 2)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	assert.True(t, called)
+}
+
+func TestErrorHandler(t *testing.T) {
+	v := validate.New(testAPI(), validate.ErrorHandler(func(w http.ResponseWriter, req *http.Request, err error) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+
+	w := httptest.NewRecorder()
+	v.Middleware(http.NotFoundHandler()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/pet/abc", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+type Animal interface {
+	Sound() string
+}
+
+type Dog struct {
+	Kind  string `json:"kind"`
+	Breed string `json:"breed" required:"true"`
+}
+
+func (Dog) Sound() string { return "woof" }
+
+type Bird struct {
+	Kind     string `json:"kind"`
+	Wingspan int    `json:"wingspan" minimum:"1"`
+}
+
+func (Bird) Sound() string { return "tweet" }
+
+type Owner struct {
+	Pets []Animal `json:"pets"`
+}
+
+func TestCheckSubTypes(t *testing.T) {
+	swagger.UsePackageName = false
+	swagger.RegisterSubTypes((*Animal)(nil), "kind", Dog{}, Bird{})
+
+	api := swag.New(swag.Endpoints(endpoint.New("post", "/owner", "Add an owner",
+		endpoint.Body(Owner{}, "owner", true),
+	)))
+	v := validate.New(api, validate.DisallowUnknownFields())
+
+	body := `{"pets":[{"kind":"Dog","breed":"collie"},{"kind":"Bird","wingspan":20}]}`
+	req := httptest.NewRequest(http.MethodPost, "/owner", strings.NewReader(body))
+	assert.Nil(t, v.Check(req))
+
+	body = `{"pets":[{"kind":"Dog"},{"kind":"Bird","wingspan":0,"breed":"collie"},{"kind":"Cat"}]}`
+	req = httptest.NewRequest(http.MethodPost, "/owner", strings.NewReader(body))
+	assert.Equal(t, []validate.Violation{
+		{In: "body", Field: "pets[0].breed", Message: "is required"},
+		{In: "body", Field: "pets[1].breed", Message: "is not allowed"},
+		{In: "body", Field: "pets[1].wingspan", Message: "must be greater than or equal to 1"},
+		{In: "body", Field: "pets[2].kind", Message: "must be one of [Dog Bird]"},
+	}, violations(v.Check(req)))
+}
+
+func TestCheckSubTypeNames(t *testing.T) {
+	swagger.UsePackageName = false
+	swagger.RegisterSubTypes((*Animal)(nil), "kind", Dog{}, Bird{})
+
+	api := swag.New(swag.Endpoints(endpoint.New("post", "/owner", "Add an owner",
+		endpoint.Body(Owner{}, "owner", true),
+	)))
+	v := validate.New(api)
+
+	// an existing definition that is not a subtype is rejected by the discriminator enum
+	body := `{"pets":[{"kind":"Owner","pets":[]}]}`
+	req := httptest.NewRequest(http.MethodPost, "/owner", strings.NewReader(body))
+	assert.Equal(t, []validate.Violation{
+		{In: "body", Field: "pets[0].kind", Message: "must be one of [Dog Bird]"},
+	}, violations(v.Check(req)))
+
+	// without the enum, subtypes are found by their allOf reference to the base definition
+	animal := api.Definitions["Animal"]
+	kind := animal.Properties["kind"]
+	kind.Enum = nil
+	animal.Properties = map[string]swagger.Property{"kind": kind}
+	api.Definitions["Animal"] = animal
+	v = validate.New(api)
+
+	body = `{"pets":[{"kind":"Dog"},{"kind":"Owner","pets":[]},{"kind":"Cat"}]}`
+	req = httptest.NewRequest(http.MethodPost, "/owner", strings.NewReader(body))
+	assert.Equal(t, []validate.Violation{
+		{In: "body", Field: "pets[0].breed", Message: "is required"},
+		{In: "body", Field: "pets[1].kind", Message: "must name a subtype of Animal"},
+		{In: "body", Field: "pets[2].kind", Message: "must name a subtype of Animal"},
+	}, violations(v.Check(req)))
+}